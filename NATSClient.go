@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	_ "github.com/awgh/bencrypt/bc"
@@ -29,8 +30,23 @@ var (
 	ServerToken   APIToken
 	// set in APILogin call
 	DPSessKeyCache *cache.Cache
+	// polls for a response that had not arrived yet, see RetryCount
+	getRetries  atomic.Uint64
+	postRetries atomic.Uint64
 )
 
+// RetryCount returns how many times Get ("get") or Post ("post") have waited
+// again for a server response since the process started
+func RetryCount(op string) uint64 {
+	switch op {
+	case "get":
+		return getRetries.Load()
+	case "post":
+		return postRetries.Load()
+	}
+	return 0
+}
+
 func init() {
 
 	DPSessKeyCache = cache.New(8*time.Hour, 8*time.Hour) // Session Key cache expires with JWT
@@ -904,6 +920,7 @@ func Get(server string, dopts Dopts, token APIToken) *NATSResponse {
 		} else if err == nats.ErrTimeout {
 			retries = retries - 1
 			if retries > 0 {
+				getRetries.Add(1)
 				time.After(500 * time.Millisecond)
 				continue
 			}
@@ -1024,6 +1041,7 @@ func Post(server string, body []byte, dopts Dopts, token APIToken) *NATSResponse
 		} else if err == nats.ErrTimeout { // try again
 			retries = retries - 1
 			if retries > 0 {
+				postRetries.Add(1)
 				waitMulti = waitMulti * 2 // double the wait time each loop
 				//fmt.Printf("wait %v\n",waitMulti)
 				time.After(1000 * time.Millisecond) // wait one second before retry
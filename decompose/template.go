@@ -0,0 +1,322 @@
+// Package decompose provides task decomposition strategies for the delegation
+// engine (Section 4.1). The template decomposer ships with the framework; other
+// strategies, such as an LLM-backed planner, implement the same engine.Decomposer
+// interface.
+package decompose
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+	"gopkg.in/yaml.v3"
+)
+
+// ─── Recipes ─────────────────────────────────────────────────────────────────
+
+// RecipeFile is the on-disk layout of a JSON or YAML recipe file.
+type RecipeFile struct {
+	Recipes []Recipe `json:"recipes" yaml:"recipes"`
+}
+
+// Recipe describes how to split tasks that match it.
+type Recipe struct {
+	Name     string          `json:"name" yaml:"name"`
+	Match    RecipeMatch     `json:"match" yaml:"match"`
+	SubTasks []SubTaskRecipe `json:"sub_tasks" yaml:"sub_tasks"`
+	
+	titleRe *regexp.Regexp
+}
+
+// RecipeMatch selects the tasks a recipe applies to. Both conditions must hold
+// when set; a recipe with an empty match applies to every task.
+type RecipeMatch struct {
+	TitlePattern string   `json:"title_pattern,omitempty" yaml:"title_pattern,omitempty"` // Regexp on TaskSpec.Title
+	Capabilities []string `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`   // All must be required by the task
+}
+
+// SubTaskRecipe is a template for one sub-task. Title and Description are Go
+// text/templates evaluated against the parent TaskSpec. Shares split the
+// parent's budget and duration; sub-tasks that set no budget share split what
+// the others leave evenly, and other unset numeric fields inherit from the parent.
+type SubTaskRecipe struct {
+	Key                  string           `json:"key" yaml:"key"` // Appended to the parent ID: {parent}-{key}
+	Title                string           `json:"title" yaml:"title"`
+	Description          string           `json:"description,omitempty" yaml:"description,omitempty"`
+	RequiredCapabilities []string         `json:"required_capabilities" yaml:"required_capabilities"`
+	DependsOn            []string         `json:"depends_on,omitempty" yaml:"depends_on,omitempty"` // Sibling keys
+	BudgetShare          float64          `json:"budget_share" yaml:"budget_share"`
+	DurationShare        float64          `json:"duration_share" yaml:"duration_share"`
+	Complexity           int              `json:"complexity,omitempty" yaml:"complexity,omitempty"`
+	Verifiability        float64          `json:"verifiability,omitempty" yaml:"verifiability,omitempty"`
+	Subjectivity         float64          `json:"subjectivity,omitempty" yaml:"subjectivity,omitempty"`
+	Criticality          t.Criticality    `json:"criticality,omitempty" yaml:"criticality,omitempty"`
+	CriticalityRationale string           `json:"criticality_rationale,omitempty" yaml:"criticality_rationale,omitempty"`
+	AutonomyLevel        t.AutonomyLevel  `json:"autonomy_level,omitempty" yaml:"autonomy_level,omitempty"`
+	MonitoringMode       t.MonitoringMode `json:"monitoring_mode,omitempty" yaml:"monitoring_mode,omitempty"`
+	IsLeaf               bool             `json:"is_leaf" yaml:"is_leaf"`
+}
+
+// ─── Template Decomposer ─────────────────────────────────────────────────────
+
+// TemplateDecomposer splits tasks using the first recipe that matches them.
+type TemplateDecomposer struct {
+	Recipes []Recipe
+}
+
+// NewTemplateDecomposer loads recipes from the given files or directories.
+// Directories are scanned for *.json, *.yaml and *.yml files in name order.
+func NewTemplateDecomposer(paths ...string) (*TemplateDecomposer, error) {
+	d := &TemplateDecomposer{}
+	for _, path := range paths {
+		files, err := recipeFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			recipes, err := LoadRecipes(f)
+			if err != nil {
+				return nil, err
+			}
+			d.Recipes = append(d.Recipes, recipes...)
+		}
+	}
+	return d, nil
+}
+
+// LoadRecipes parses a single JSON or YAML recipe file, chosen by extension.
+func LoadRecipes(path string) ([]Recipe, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	
+	var file RecipeFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("recipe file %s: unsupported extension", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse recipe file %s: %w", path, err)
+	}
+	
+	for i := range file.Recipes {
+		if err := file.Recipes[i].compile(); err != nil {
+			return nil, fmt.Errorf("recipe file %s: %w", path, err)
+		}
+	}
+	return file.Recipes, nil
+}
+
+// Decompose implements engine.Decomposer.
+func (d *TemplateDecomposer) Decompose(parent t.TaskSpec) ([]t.TaskSpec, error) {
+	for i := range d.Recipes {
+		r := &d.Recipes[i]
+		if r.matches(parent) {
+			return r.expand(parent)
+		}
+	}
+	return nil, fmt.Errorf("no decomposition recipe matches task %s (%q)", parent.TaskID, parent.Title)
+}
+
+func (r *Recipe) compile() error {
+	if r.Match.TitlePattern != "" {
+		re, err := regexp.Compile(r.Match.TitlePattern)
+		if err != nil {
+			return fmt.Errorf("recipe %s: title pattern: %w", r.Name, err)
+		}
+		r.titleRe = re
+	}
+	keys := make(map[string]bool, len(r.SubTasks))
+	for _, st := range r.SubTasks {
+		if st.Key == "" {
+			return fmt.Errorf("recipe %s: sub-task without key", r.Name)
+		}
+		if keys[st.Key] {
+			return fmt.Errorf("recipe %s: duplicate sub-task key %q", r.Name, st.Key)
+		}
+		keys[st.Key] = true
+	}
+	if _, err := r.budgetShares(); err != nil {
+		return fmt.Errorf("recipe %s: budget shares: %w", r.Name, err)
+	}
+	return nil
+}
+
+// budgetShares returns each sub-task's budget share, splitting what the set
+// shares leave evenly among the unset ones. A zero budget means unlimited
+// downstream, so a recipe whose set shares leave nothing for the unset ones
+// is rejected. Duration shares are not split: sub-tasks may run in parallel.
+func (r *Recipe) budgetShares() ([]float64, error) {
+	out := make([]float64, len(r.SubTasks))
+	var set float64
+	var unset int
+	for i, st := range r.SubTasks {
+		switch v := st.BudgetShare; {
+		case v < 0:
+			return nil, fmt.Errorf("sub-task %s has a negative share", st.Key)
+		case v == 0:
+			unset++
+		default:
+			out[i] = v
+			set += v
+		}
+	}
+	if set > 1+1e-9 {
+		return nil, fmt.Errorf("shares total %.2f, more than the whole", set)
+	}
+	if unset == 0 {
+		return out, nil
+	}
+	if set >= 1 {
+		return nil, fmt.Errorf("shares already total %.2f; %d sub-tasks set none", set, unset)
+	}
+	for i := range out {
+		if out[i] == 0 {
+			out[i] = (1 - set) / float64(unset)
+		}
+	}
+	return out, nil
+}
+
+func (r *Recipe) matches(task t.TaskSpec) bool {
+	if r.Match.TitlePattern != "" {
+		if r.titleRe == nil {
+			if err := r.compile(); err != nil {
+				return false
+			}
+		}
+		if !r.titleRe.MatchString(task.Title) {
+			return false
+		}
+	}
+	have := make(map[string]bool, len(task.RequiredCapabilities))
+	for _, c := range task.RequiredCapabilities {
+		have[c] = true
+	}
+	for _, c := range r.Match.Capabilities {
+		if !have[c] {
+			return false
+		}
+	}
+	return true
+}
+
+// expand instantiates the recipe's sub-tasks for a parent.
+func (r *Recipe) expand(parent t.TaskSpec) ([]t.TaskSpec, error) {
+	budgetShares, err := r.budgetShares()
+	if err != nil {
+		return nil, fmt.Errorf("recipe %s: budget shares: %w", r.Name, err)
+	}
+	
+	subs := make([]t.TaskSpec, 0, len(r.SubTasks))
+	for i, st := range r.SubTasks {
+		title, err := render(st.Title, parent)
+		if err != nil {
+			return nil, fmt.Errorf("recipe %s/%s title: %w", r.Name, st.Key, err)
+		}
+		desc, err := render(st.Description, parent)
+		if err != nil {
+			return nil, fmt.Errorf("recipe %s/%s description: %w", r.Name, st.Key, err)
+		}
+		
+		sub := t.TaskSpec{
+			TaskID:               subTaskID(parent.TaskID, st.Key),
+			Title:                title,
+			Description:          desc,
+			Criticality:          st.Criticality,
+			Priority:             parent.Priority,
+			CriticalityRationale: st.CriticalityRationale,
+			Complexity:           st.Complexity,
+			Uncertainty:          parent.Uncertainty,
+			EstimatedDuration:    int64(st.DurationShare * float64(parent.EstimatedDuration)),
+			MaxBudget:            budgetShares[i] * parent.MaxBudget,
+			Reversible:           parent.Reversible,
+			Verifiability:        st.Verifiability,
+			Subjectivity:         st.Subjectivity,
+			ContextSensitivity:   parent.ContextSensitivity,
+			IsLeaf:               st.IsLeaf,
+			RequiredCapabilities: st.RequiredCapabilities,
+			AutonomyLevel:        st.AutonomyLevel,
+			MonitoringMode:       st.MonitoringMode,
+			Permissions:          parent.Permissions,
+		}
+		for _, dep := range st.DependsOn {
+			sub.DependsOn = append(sub.DependsOn, subTaskID(parent.TaskID, dep))
+		}
+		if sub.Criticality == "" {
+			sub.Criticality = parent.Criticality
+		}
+		if sub.Complexity == 0 {
+			sub.Complexity = parent.Complexity
+		}
+		if sub.Verifiability == 0 {
+			sub.Verifiability = parent.Verifiability
+		}
+		if sub.AutonomyLevel == "" {
+			sub.AutonomyLevel = t.AutonomyAtomic
+		}
+		if sub.MonitoringMode == "" {
+			sub.MonitoringMode = parent.MonitoringMode
+		}
+		if parent.Deadline != nil {
+			deadline := *parent.Deadline
+			sub.Deadline = &deadline
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+func subTaskID(parentID, key string) string {
+	return fmt.Sprintf("%s-%s", parentID, key)
+}
+
+// render evaluates a text/template against the parent task.
+func render(text string, parent t.TaskSpec) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("recipe").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, parent); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// recipeFiles expands a path into the recipe files it names.
+func recipeFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	
+	var files []string
+	for _, pattern := range []string{"*.json", "*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(path, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
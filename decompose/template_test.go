@@ -0,0 +1,62 @@
+package decompose
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBudgetShares(t *testing.T) {
+	recipe := func(shares ...float64) *Recipe {
+		r := &Recipe{Name: "r"}
+		for i, s := range shares {
+			r.SubTasks = append(r.SubTasks, SubTaskRecipe{Key: string(rune('a' + i)), BudgetShare: s})
+		}
+		return r
+	}
+	tests := []struct {
+		name    string
+		recipe  *Recipe
+		want    []float64
+		wantErr bool
+	}{
+		{"all set", recipe(0.5, 0.3, 0.2), []float64{0.5, 0.3, 0.2}, false},
+		{"set below the whole", recipe(0.5, 0.3), []float64{0.5, 0.3}, false},
+		{"none set", recipe(0, 0, 0, 0), []float64{0.25, 0.25, 0.25, 0.25}, false},
+		{"remainder split evenly", recipe(0.4, 0, 0), []float64{0.4, 0.3, 0.3}, false},
+		{"rounding within tolerance", recipe(0.1, 0.2, 0.7000000001), []float64{0.1, 0.2, 0.7000000001}, false},
+		{"no sub-tasks", recipe(), []float64{}, false},
+		{"over the whole", recipe(0.6, 0.6), nil, true},
+		{"nothing left for unset", recipe(0.5, 0.5, 0), nil, true},
+		{"negative", recipe(0.5, -0.1), nil, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.recipe.budgetShares()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("budgetShares() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("budgetShares() = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tc.want[i]) > 1e-9 {
+					t.Errorf("budgetShares() = %v, want %v", got, tc.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestShippedRecipesLoad(t *testing.T) {
+	d, err := NewTemplateDecomposer("../recipes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Recipes) == 0 {
+		t.Fatal("no recipes loaded from ../recipes")
+	}
+}
@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// DefaultMaxExtensions applies when a contract's terms leave MaxExtensions unset.
+const DefaultMaxExtensions = 2
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// CONTRACT AMENDMENTS (Section 4.2)
+// Terms change only by mutual consent: one party proposes, the counter-party
+// accepts or rejects. Each accepted amendment produces a new contract version.
+// Stored under Contracts/{contract_id}/{amendment_id}, indexed in "amendments".
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// ProposeAmendment records a proposed change to a contract's terms and notifies
+// the counter-party on the agent channel. Either party may propose.
+func (e *Engine) ProposeAmendment(amendment t.ContractAmendment) (*t.ContractAmendment, error) {
+	contract, err := e.GetContract(amendment.ContractID)
+	if err != nil {
+		return nil, fmt.Errorf("get contract: %w", err)
+	}
+	if contract.Status != t.ContractActive {
+		return nil, fmt.Errorf("contract %s is %s; only active contracts can be amended",
+			contract.ContractID, contract.Status)
+	}
+	if e.SelfID != contract.DelegatorID && e.SelfID != contract.DelegateeID {
+		return nil, fmt.Errorf("%s is not a party to contract %s", e.SelfID, contract.ContractID)
+	}
+	if amendment.NewMaxCost == nil && amendment.NewDeadline == nil && amendment.NewInterval == nil {
+		return nil, fmt.Errorf("amendment to %s changes nothing", contract.ContractID)
+	}
+	
+	// Competing proposals against the same version each keep their own record
+	amendment.ProposedAt = time.Now()
+	amendment.AmendmentID = fmt.Sprintf("amend_%s_v%d_%d", contract.ContractID, contract.Version+1, amendment.ProposedAt.UnixNano())
+	amendment.TaskID = contract.TaskID
+	amendment.ProposedBy = e.SelfID
+	amendment.BaseVersion = contract.Version
+	amendment.TargetVersion = contract.Version + 1
+	amendment.Status = t.AmendmentProposed
+	amendment.RespondedAt = nil
+	
+	if err := e.storeAmendment(&amendment); err != nil {
+		return nil, err
+	}
+	if err := e.appendList(DomainContracts, contract.ContractID, "amendments", amendment.AmendmentID); err != nil {
+		return nil, fmt.Errorf("index amendment %s: %w", amendment.AmendmentID, err)
+	}
+	
+	channelName := agentChannelName(contract.TaskID, contract.DelegatorID, contract.DelegateeID)
+	if err := e.publishAgentMessage(channelName, contract.TaskID, t.MsgAmendmentProposal, amendment); err != nil {
+		log.Printf("Notify amendment %s: %v", amendment.AmendmentID, err)
+	}
+	
+	log.Printf("Amendment %s proposed by %s: %s", amendment.AmendmentID, e.SelfID, amendment.Reason)
+	return &amendment, nil
+}
+
+// GetAmendment retrieves a proposed amendment.
+func (e *Engine) GetAmendment(contractID, amendmentID string) (*t.ContractAmendment, error) {
+	data, err := e.retrieveData(DomainContracts, contractID, amendmentID)
+	if err != nil {
+		return nil, err
+	}
+	var amendment t.ContractAmendment
+	if err := json.Unmarshal(data, &amendment); err != nil {
+		return nil, fmt.Errorf("unmarshal amendment: %w", err)
+	}
+	return &amendment, nil
+}
+
+// ListAmendments returns every amendment proposed on a contract, oldest first,
+// whatever its outcome.
+func (e *Engine) ListAmendments(contractID string) ([]t.ContractAmendment, error) {
+	var ids []string
+	if _, err := e.retrieveJSON(DomainContracts, contractID, "amendments", &ids); err != nil {
+		return nil, err
+	}
+	amendments := make([]t.ContractAmendment, 0, len(ids))
+	for _, id := range ids {
+		amendment, err := e.GetAmendment(contractID, id)
+		if err != nil {
+			return nil, fmt.Errorf("get amendment %s: %w", id, err)
+		}
+		amendments = append(amendments, *amendment)
+	}
+	return amendments, nil
+}
+
+// RespondToAmendment accepts or rejects an amendment. Only the counter-party of
+// the proposer may respond. Acceptance applies the new terms as a new contract
+// version and propagates budget/deadline changes to the task.
+func (e *Engine) RespondToAmendment(contractID, amendmentID string, accept bool, reason string) (*t.DelegationContract, error) {
+	amendment, err := e.GetAmendment(contractID, amendmentID)
+	if err != nil {
+		return nil, fmt.Errorf("get amendment: %w", err)
+	}
+	if amendment.Status != t.AmendmentProposed {
+		return nil, fmt.Errorf("amendment %s already %s", amendmentID, amendment.Status)
+	}
+	contract, err := e.GetContract(contractID)
+	if err != nil {
+		return nil, fmt.Errorf("get contract: %w", err)
+	}
+	if e.SelfID == amendment.ProposedBy ||
+		(e.SelfID != contract.DelegatorID && e.SelfID != contract.DelegateeID) {
+		return nil, fmt.Errorf("%s cannot respond to amendment %s", e.SelfID, amendmentID)
+	}
+	
+	now := time.Now()
+	amendment.RespondedAt = &now
+	amendment.ResponseReason = reason
+	switch {
+	case contract.Version != amendment.BaseVersion || contract.Status != t.ContractActive:
+		amendment.Status = t.AmendmentStale
+	case accept:
+		amendment.Status = t.AmendmentAccepted
+	default:
+		amendment.Status = t.AmendmentRejected
+	}
+	if err := e.storeAmendment(amendment); err != nil {
+		return nil, err
+	}
+	
+	channelName := agentChannelName(contract.TaskID, contract.DelegatorID, contract.DelegateeID)
+	if err := e.publishAgentMessage(channelName, contract.TaskID, t.MsgAmendmentResponse, amendment); err != nil {
+		log.Printf("Notify amendment response %s: %v", amendmentID, err)
+	}
+	
+	if amendment.Status != t.AmendmentAccepted {
+		log.Printf("Amendment %s %s by %s", amendmentID, amendment.Status, e.SelfID)
+		return contract, nil
+	}
+	if err := e.applyAmendment(contract, amendment); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+// applyAmendment writes the amended terms as the next contract version.
+func (e *Engine) applyAmendment(contract *t.DelegationContract, amendment *t.ContractAmendment) error {
+	if amendment.IsExtension(contract.Terms) {
+		contract.Extensions++
+	}
+	if amendment.NewMaxCost != nil {
+		contract.Terms.MaxCost = *amendment.NewMaxCost
+	}
+	if amendment.NewDeadline != nil {
+		contract.Terms.Deadline = *amendment.NewDeadline
+	}
+	if amendment.NewInterval != nil {
+		contract.Terms.ReportingInterval = *amendment.NewInterval
+	}
+	contract.Version++
+	if err := e.storeContract(contract); err != nil {
+		return fmt.Errorf("store contract v%d: %w", contract.Version, err)
+	}
+	
+	task, err := e.GetTask(contract.TaskID)
+	if err != nil {
+		return err
+	}
+	if amendment.NewMaxCost != nil {
+		task.MaxBudget = *amendment.NewMaxCost
+	}
+	if amendment.NewDeadline != nil {
+		deadline := *amendment.NewDeadline
+		task.Deadline = &deadline
+	}
+	if err := e.UpdateTask(*task); err != nil {
+		return err
+	}
+	
+	log.Printf("Contract %s amended to v%d (%d extensions)",
+		contract.ContractID, contract.Version, contract.Extensions)
+	return nil
+}
+
+// storeAmendment persists an amendment under its contract.
+func (e *Engine) storeAmendment(amendment *t.ContractAmendment) error {
+	body, err := json.Marshal(amendment)
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainContracts, amendment.ContractID, amendment.AmendmentID, body)
+}
+
+// extensionsExhausted reports whether a contract has used up its extension allowance.
+func extensionsExhausted(contract *t.DelegationContract) bool {
+	limit := contract.Terms.MaxExtensions
+	if limit <= 0 {
+		limit = DefaultMaxExtensions
+	}
+	return contract.Extensions >= limit
+}
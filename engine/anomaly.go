@@ -0,0 +1,364 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// ANOMALY DETECTION (Sections 4.4, 4.5)
+// Watches the monitoring stream for progress going backwards, spend that will
+// overrun MaxBudget, progress far slower than is normal for the task's
+// capabilities, and tasks that have stopped progressing. Findings are raised
+// as adaptive triggers with the evidence attached, so the usual response
+// cycle decides what to do about them.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// AnomalyRules configures the detector. Zero values disable the matching rule.
+type AnomalyRules struct {
+	RegressionTolerance float64       // Progress may fall by this much before it counts as going backwards
+	BudgetMargin        float64       // Projected spend may exceed MaxBudget by this fraction
+	MinProgress         float64       // Progress needed before spend is projected (avoids early noise)
+	SlowSigma           float64       // Interval progress rate this many std devs below baseline is slow
+	StallAfter          time.Duration // Minimum time without progress before a task counts as stalled
+	StallSigma          float64       // Stall also needs a gap this many std devs above the baseline gap
+	MinSamples          int           // Baseline observations required before statistical rules apply
+	Cooldown            time.Duration // Minimum time between triggers for the same task and rule
+}
+
+// DefaultAnomalyRules returns conservative settings suitable for most deployments.
+func DefaultAnomalyRules() AnomalyRules {
+	return AnomalyRules{
+		RegressionTolerance: 0.01,
+		BudgetMargin:        0.10,
+		MinProgress:         0.10,
+		SlowSigma:           2.5,
+		StallAfter:          15 * time.Minute,
+		StallSigma:          3.0,
+		MinSamples:          10,
+		Cooldown:            10 * time.Minute,
+	}
+}
+
+// AnomalyDetector keeps per-capability baselines and per-task progress history.
+// Attach one to Engine.Detector to enable detection.
+type AnomalyDetector struct {
+	Rules AnomalyRules
+	
+	mu        sync.Mutex
+	baselines map[string]*capabilityBaseline // By capability
+	tasks     map[string]*detectorTask       // By task ID
+	fired     map[string]time.Time           // Last trigger by task ID + rule
+}
+
+// capabilityBaseline tracks running statistics (Welford) of progress rate per
+// hour and of the gap between progress events, for tasks needing a capability.
+type capabilityBaseline struct {
+	rate stat
+	gap  stat
+}
+
+type stat struct {
+	n    int
+	mean float64
+	m2   float64
+}
+
+func (s *stat) add(x float64) {
+	s.n++
+	d := x - s.mean
+	s.mean += d / float64(s.n)
+	s.m2 += d * (x - s.mean)
+}
+
+func (s *stat) stddev() float64 {
+	if s.n < 2 {
+		return 0
+	}
+	return math.Sqrt(s.m2 / float64(s.n-1))
+}
+
+// detectorTask is the last progress seen for a task under its current
+// delegatee. A new delegatee starts it over.
+type detectorTask struct {
+	agentID      string
+	delegateeID  string
+	capabilities []string
+	progress     float64
+	progressAt   time.Time
+	observed     bool // Progress has been reported under this delegatee
+}
+
+// NewAnomalyDetector creates a detector with the given rules.
+func NewAnomalyDetector(rules AnomalyRules) *AnomalyDetector {
+	return &AnomalyDetector{
+		Rules:     rules,
+		baselines: make(map[string]*capabilityBaseline),
+		tasks:     make(map[string]*detectorTask),
+		fired:     make(map[string]time.Time),
+	}
+}
+
+// Baseline reports the progress-rate baseline for a capability: mean and
+// standard deviation in progress per hour, and the number of samples.
+func (d *AnomalyDetector) Baseline(capability string) (mean, stddev float64, samples int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, ok := d.baselines[capability]
+	if !ok {
+		return 0, 0, 0
+	}
+	return b.rate.mean, b.rate.stddev(), b.rate.n
+}
+
+// inspect applies the event-driven rules to one event and returns any triggers to raise.
+func (d *AnomalyDetector) inspect(task *t.TaskSpec, state *t.TaskLiveState, event t.MonitorEvent) []t.AdaptiveTrigger {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	
+	switch event.EventType {
+	case t.EventTaskCompleted, t.EventTaskFailed, t.EventTaskCancelled:
+		delete(d.tasks, task.TaskID)
+		return nil
+	}
+	
+	var found []t.AdaptiveTrigger
+	now := event.Timestamp
+	dt, seen := d.tasks[task.TaskID]
+	if seen && dt.delegateeID != task.DelegateeID {
+		seen = false // Re-allocated: the previous delegatee's pace says nothing
+	}
+	if seen && !dt.observed {
+		seen = false
+	}
+	if !seen {
+		dt = &detectorTask{
+			delegateeID:  task.DelegateeID,
+			capabilities: task.RequiredCapabilities,
+			progress:     event.Progress,
+			progressAt:   now,
+		}
+		d.tasks[task.TaskID] = dt
+	}
+	dt.agentID = event.AgentID
+	dt.observed = true
+	
+	// Progress going backwards
+	if seen && d.Rules.RegressionTolerance > 0 && event.Progress < dt.progress-d.Rules.RegressionTolerance {
+		found = d.appendFinding(found, task, dt.agentID, t.TriggerIntPerfDrop, t.AnomalyEvidence{
+			Rule:      "progress_regression",
+			Observed:  event.Progress,
+			Threshold: dt.progress - d.Rules.RegressionTolerance,
+			EventID:   event.EventID,
+			Detail:    fmt.Sprintf("progress fell from %.0f%% to %.0f%%", dt.progress*100, event.Progress*100),
+		}, now)
+	}
+	
+	// Spend on course to exceed the budget
+	if d.Rules.BudgetMargin > 0 && state != nil && state.MaxBudget > 0 && state.Progress >= d.Rules.MinProgress {
+		limit := state.MaxBudget * (1 + d.Rules.BudgetMargin)
+		if state.ProjectedSpend > limit {
+			found = d.appendFinding(found, task, dt.agentID, t.TriggerIntBudgetOverrun, t.AnomalyEvidence{
+				Rule:      "budget_projection",
+				Observed:  state.ProjectedSpend,
+				Threshold: limit,
+				EventID:   event.EventID,
+				Detail: fmt.Sprintf("spent %.2f at %.0f%% progress; projected %.2f against budget %.2f",
+					state.Spend, state.Progress*100, state.ProjectedSpend, state.MaxBudget),
+			}, now)
+		}
+	}
+	
+	// Interval progress rate against the capability baseline, then learn from it
+	if seen && event.Progress > dt.progress {
+		hours := now.Sub(dt.progressAt).Hours()
+		if hours > 0 {
+			rate := (event.Progress - dt.progress) / hours
+			if capability, b := d.bestBaseline(dt.capabilities); b != nil && d.Rules.SlowSigma > 0 &&
+				b.rate.n >= d.Rules.MinSamples && b.rate.stddev() > 0 {
+				threshold := b.rate.mean - d.Rules.SlowSigma*b.rate.stddev()
+				if rate < threshold {
+					found = d.appendFinding(found, task, dt.agentID, t.TriggerIntPerfDrop, t.AnomalyEvidence{
+						Rule:           "slow_progress",
+						Observed:       rate,
+						Threshold:      threshold,
+						Capability:     capability,
+						BaselineMean:   b.rate.mean,
+						BaselineStdDev: b.rate.stddev(),
+						Samples:        b.rate.n,
+						EventID:        event.EventID,
+						Detail:         fmt.Sprintf("progressing at %.3f/h against a %s norm of %.3f/h", rate, capability, b.rate.mean),
+					}, now)
+				}
+			}
+			for _, capability := range dt.capabilities {
+				b := d.baseline(capability)
+				b.rate.add(rate)
+				b.gap.add(hours)
+			}
+		}
+	}
+	if event.Progress != dt.progress || !seen {
+		dt.progress = event.Progress
+		dt.progressAt = now
+	}
+	return found
+}
+
+// sweep applies the stall rule to every tracked task as of now. Only assigned
+// and in-progress tasks can stall; a paused or checkpointing task is idle by
+// design, and a re-allocated one starts its clock again with the new delegatee.
+func (d *AnomalyDetector) sweep(tasks map[string]*t.TaskSpec, now time.Time) []t.AdaptiveTrigger {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	
+	var found []t.AdaptiveTrigger
+	if d.Rules.StallAfter <= 0 {
+		return found
+	}
+	for id, dt := range d.tasks {
+		task, ok := tasks[id]
+		if !ok {
+			continue
+		}
+		if task.Status != t.TaskAssigned && task.Status != t.TaskInProgress {
+			continue
+		}
+		if dt.delegateeID != task.DelegateeID {
+			d.tasks[id] = &detectorTask{
+				agentID:      task.DelegateeID,
+				delegateeID:  task.DelegateeID,
+				capabilities: dt.capabilities,
+				progressAt:   now,
+			}
+			continue
+		}
+		
+		threshold := d.Rules.StallAfter
+		ev := t.AnomalyEvidence{Rule: "stalled"}
+		if capability, b := d.bestBaseline(dt.capabilities); b != nil && b.gap.n >= d.Rules.MinSamples {
+			norm := time.Duration((b.gap.mean + d.Rules.StallSigma*b.gap.stddev()) * float64(time.Hour))
+			if norm > threshold {
+				threshold = norm
+			}
+			ev.Capability = capability
+			ev.BaselineMean = b.gap.mean * 3600
+			ev.BaselineStdDev = b.gap.stddev() * 3600
+			ev.Samples = b.gap.n
+		}
+		
+		idle := now.Sub(dt.progressAt)
+		if idle <= threshold {
+			continue
+		}
+		ev.Observed = idle.Seconds()
+		ev.Threshold = threshold.Seconds()
+		ev.Detail = fmt.Sprintf("no progress for %s (stuck at %.0f%%)", idle.Round(time.Second), dt.progress*100)
+		found = d.appendFinding(found, task, dt.agentID, t.TriggerIntPerfDrop, ev, now)
+	}
+	return found
+}
+
+// appendFinding adds a trigger unless the same rule fired for the task within the cooldown.
+func (d *AnomalyDetector) appendFinding(found []t.AdaptiveTrigger, task *t.TaskSpec, agentID string,
+	kind t.TriggerType, ev t.AnomalyEvidence, now time.Time) []t.AdaptiveTrigger {
+	key := task.TaskID + "|" + ev.Rule
+	if last, ok := d.fired[key]; ok && now.Sub(last) < d.Rules.Cooldown {
+		return found
+	}
+	d.fired[key] = now
+	
+	ev.DetectedAt = now
+	return append(found, t.AdaptiveTrigger{
+		TriggerID:   fmt.Sprintf("anomaly_%s_%s_%d", ev.Rule, task.TaskID, now.UnixNano()),
+		TaskID:      task.TaskID,
+		Type:        kind,
+		AgentID:     agentID,
+		Description: "anomaly detector: " + ev.Detail,
+		Evidence:    &ev,
+	})
+}
+
+// bestBaseline picks the baseline with the most samples among the capabilities.
+func (d *AnomalyDetector) bestBaseline(capabilities []string) (string, *capabilityBaseline) {
+	var name string
+	var best *capabilityBaseline
+	for _, c := range capabilities {
+		if b, ok := d.baselines[c]; ok && (best == nil || b.rate.n > best.rate.n) {
+			name, best = c, b
+		}
+	}
+	return name, best
+}
+
+func (d *AnomalyDetector) baseline(capability string) *capabilityBaseline {
+	b, ok := d.baselines[capability]
+	if !ok {
+		b = &capabilityBaseline{}
+		d.baselines[capability] = b
+	}
+	return b
+}
+
+// detectAnomalies runs the event-driven rules for an event the aggregator accepted.
+func (e *Engine) detectAnomalies(event t.MonitorEvent) {
+	task, err := e.GetTask(event.TaskID)
+	if err != nil {
+		return
+	}
+	state, err := e.GetLiveState(event.TaskID)
+	if err != nil {
+		state = nil
+	}
+	e.raiseAnomalies(e.Detector.inspect(task, state, event))
+}
+
+// SweepAnomalies applies the time-based stall rule to every task the detector
+// is tracking under continuous monitoring; periodic, event-triggered and
+// outcome-only tasks batch or withhold progress, so silence proves nothing.
+// Call it periodically, or use StartAnomalySweeps.
+func (e *Engine) SweepAnomalies() {
+	if e.Detector == nil {
+		return
+	}
+	e.Detector.mu.Lock()
+	ids := make([]string, 0, len(e.Detector.tasks))
+	for id := range e.Detector.tasks {
+		ids = append(ids, id)
+	}
+	e.Detector.mu.Unlock()
+	
+	tasks := make(map[string]*t.TaskSpec, len(ids))
+	for _, id := range ids {
+		task, err := e.GetTask(id)
+		if err != nil {
+			continue
+		}
+		if mode, _ := e.monitoringTerms(id); mode != t.MonitorContinuous {
+			continue
+		}
+		tasks[id] = task
+	}
+	e.raiseAnomalies(e.Detector.sweep(tasks, time.Now()))
+}
+
+// StartAnomalySweeps runs SweepAnomalies every interval until stop is called.
+func (e *Engine) StartAnomalySweeps(interval time.Duration) (stop func()) {
+	return runEvery(interval, e.SweepAnomalies)
+}
+
+// raiseAnomalies hands findings to the adaptive response cycle. Called without
+// detector locks held, since responses emit events of their own.
+func (e *Engine) raiseAnomalies(triggers []t.AdaptiveTrigger) {
+	for _, trigger := range triggers {
+		log.Printf("ANOMALY [%s] on task %s: %s", trigger.Evidence.Rule, trigger.TaskID, trigger.Evidence.Detail)
+		if err := e.RaiseTrigger(trigger); err != nil {
+			log.Printf("Raise anomaly trigger on %s: %v", trigger.TaskID, err)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package engine
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// TAMPER-EVIDENT AUDIT LOG (Sections 4.5, 4.8)
+// Every monitoring event and trigger recorded for a task is also appended to a
+// hash chain. Each engine keeps its own chain per task, under
+// Monitoring/{task}/audit_{signer}_{n} with audit_head_{signer}, so delegator
+// and delegatee never race for a sequence. Each record carries the previous
+// record's hash and is signed by the emitting engine under a key ID that stays
+// on its profile after rotation, so gaps, reordering and edits are detectable
+// after the fact by VerifyAuditLog.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// Audit record kinds.
+const (
+	AuditMonitorEvent = "monitor_event"
+	AuditTrigger      = "trigger"
+)
+
+// SetSigningKey sets the key this engine signs audit records with and
+// publishes its public half on the engine's agent profile for verifiers.
+// Earlier keys stay published under their key IDs so old records still verify.
+func (e *Engine) SetSigningKey(key ed25519.PrivateKey) error {
+	e.SigningKey = key
+	agent, err := e.GetAgent(e.SelfID)
+	if err != nil {
+		return fmt.Errorf("get agent %s: %w", e.SelfID, err)
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	agent.SigningKey = base64.StdEncoding.EncodeToString(pub)
+	if agent.SigningKeys == nil {
+		agent.SigningKeys = make(map[string]string)
+	}
+	agent.SigningKeys[auditKeyID(pub)] = agent.SigningKey
+	return e.saveAgent(*agent)
+}
+
+// appendAudit links a stored record into this engine's audit chain for the task.
+func (e *Engine) appendAudit(taskID, kind, recordID string, payload []byte) error {
+	e.auditMu.Lock()
+	defer e.auditMu.Unlock()
+	
+	if err := e.registerWriter(DomainMonitoring, taskID, "audit_head"); err != nil {
+		return err
+	}
+	head, _, err := e.auditHead(taskID, e.SelfID)
+	if err != nil {
+		return err
+	}
+	
+	record := t.AuditRecord{
+		TaskID:     taskID,
+		Sequence:   head.Sequence + 1,
+		Kind:       kind,
+		RecordID:   recordID,
+		Payload:    payload,
+		PrevHash:   head.Hash,
+		SignerID:   e.SelfID,
+		RecordedAt: time.Now().UTC(), // Stable encoding across hash round trips
+	}
+	if e.SigningKey != nil {
+		record.KeyID = auditKeyID(e.SigningKey.Public().(ed25519.PublicKey))
+	}
+	record.Hash, err = auditHash(record)
+	if err != nil {
+		return err
+	}
+	if e.SigningKey != nil {
+		digest, _ := hex.DecodeString(record.Hash)
+		record.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(e.SigningKey, digest))
+	}
+	
+	body, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := e.storeData(DomainMonitoring, taskID, auditAspect(e.SelfID, record.Sequence), body); err != nil {
+		return fmt.Errorf("store audit record %d: %w", record.Sequence, err)
+	}
+	
+	body, err = json.Marshal(t.AuditHead{Sequence: record.Sequence, Hash: record.Hash})
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainMonitoring, taskID, e.shardAspect("audit_head"), body)
+}
+
+// GetAuditLog returns a task's audit records from every signer's chain in
+// time order, each chain stopping at its first missing sequence number.
+func (e *Engine) GetAuditLog(taskID string) ([]t.AuditRecord, error) {
+	signers, err := e.auditSigners(taskID)
+	if err != nil {
+		return nil, err
+	}
+	var records []t.AuditRecord
+	for _, signer := range signers {
+		head, _, err := e.auditHead(taskID, signer)
+		if err != nil {
+			return nil, err
+		}
+		for seq := uint64(1); seq <= head.Sequence; seq++ {
+			record, err := e.getAuditRecord(taskID, signer, seq)
+			if isNotFound(err) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, *record)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].RecordedAt.Before(records[j].RecordedAt)
+	})
+	return records, nil
+}
+
+// VerifyAuditLog walks every signer's audit chain for a task from the first
+// record to the head and reports gaps, reordered or modified records, broken
+// hash links, bad or missing signatures and lost heads. A task with no audit
+// records at all is never reported valid.
+func (e *Engine) VerifyAuditLog(taskID string) (*t.AuditReport, error) {
+	signers, err := e.auditSigners(taskID)
+	if err != nil {
+		return nil, err
+	}
+	
+	report := &t.AuditReport{TaskID: taskID, VerifiedAt: time.Now()}
+	keys := make(map[string]ed25519.PublicKey)
+	for _, signer := range signers {
+		if err := e.verifyAuditChain(taskID, signer, report, keys); err != nil {
+			return nil, err
+		}
+	}
+	if report.Records == 0 {
+		report.Issues = append(report.Issues, t.AuditIssue{
+			Problem: "empty",
+			Detail:  fmt.Sprintf("no audit records found for task %s", taskID),
+		})
+	}
+	
+	report.Valid = len(report.Issues) == 0
+	return report, nil
+}
+
+// verifyAuditChain checks one signer's chain, adding its records and issues to report.
+func (e *Engine) verifyAuditChain(taskID, signer string, report *t.AuditReport, keys map[string]ed25519.PublicKey) error {
+	issue := func(seq uint64, problem, format string, args ...interface{}) {
+		report.Issues = append(report.Issues, t.AuditIssue{
+			SignerID: signer,
+			Sequence: seq,
+			Problem:  problem,
+			Detail:   fmt.Sprintf(format, args...),
+		})
+	}
+	
+	head, found, err := e.auditHead(taskID, signer)
+	if err != nil {
+		return err
+	}
+	last := head.Sequence
+	if !found {
+		// Without a head the chain is only sound if the signer never wrote to it
+		for {
+			_, err := e.getAuditRecord(taskID, signer, last+1)
+			if isNotFound(err) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			last++
+		}
+		if last == 0 {
+			return nil
+		}
+		issue(0, "head_missing", "%d records from %s but no chain head", last, signer)
+	}
+	
+	prevHash := ""
+	for seq := uint64(1); seq <= last; seq++ {
+		record, err := e.getAuditRecord(taskID, signer, seq)
+		if isNotFound(err) {
+			issue(seq, "gap", "record %d is missing", seq)
+			prevHash = "" // Cannot check the next link against a missing record
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		report.Records++
+		
+		var key ed25519.PublicKey
+		if record.Signature != "" {
+			cacheKey := signer + "|" + record.KeyID
+			var ok bool
+			if key, ok = keys[cacheKey]; !ok {
+				key = e.signerKey(signer, record.KeyID)
+				keys[cacheKey] = key
+			}
+		}
+		issues, err := auditRecordIssues(taskID, signer, seq, *record, prevHash, key)
+		if err != nil {
+			return err
+		}
+		report.Issues = append(report.Issues, issues...)
+		prevHash = record.Hash
+	}
+	
+	if found && last > 0 && prevHash != "" && prevHash != head.Hash {
+		issue(last, "head_mismatch", "head is %s, last record is %s", short(head.Hash), short(prevHash))
+	}
+	// A record past the head was written without advancing it, or the head was rolled back
+	if found {
+		_, err := e.getAuditRecord(taskID, signer, last+1)
+		if err == nil {
+			issue(last+1, "unindexed", "record %d exists beyond the head", last+1)
+		} else if !isNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditRecordIssues checks the record read from slot seq of signer's chain:
+// that it belongs there, that its content matches its hash, that it links to
+// prevHash (empty when the previous record could not be read) and that key
+// verifies its signature.
+func auditRecordIssues(taskID, signer string, seq uint64, record t.AuditRecord, prevHash string, key ed25519.PublicKey) ([]t.AuditIssue, error) {
+	var issues []t.AuditIssue
+	issue := func(problem, format string, args ...interface{}) {
+		issues = append(issues, t.AuditIssue{
+			SignerID: signer,
+			Sequence: seq,
+			Problem:  problem,
+			Detail:   fmt.Sprintf(format, args...),
+		})
+	}
+	
+	if record.Sequence != seq || record.TaskID != taskID || record.SignerID != signer {
+		issue("reordered", "slot %d holds record %d of task %s from %s",
+			seq, record.Sequence, record.TaskID, record.SignerID)
+	}
+	hash, err := auditHash(record)
+	if err != nil {
+		return nil, err
+	}
+	if hash != record.Hash {
+		issue("modified", "content hashes to %s, record claims %s", short(hash), short(record.Hash))
+	}
+	if seq == 1 && record.PrevHash != "" {
+		issue("broken_link", "first record links to %s", short(record.PrevHash))
+	} else if seq > 1 && prevHash != "" && record.PrevHash != prevHash {
+		issue("broken_link", "links to %s, previous record is %s", short(record.PrevHash), short(prevHash))
+	}
+	
+	if record.Signature == "" {
+		issue("unsigned", "record has no signature from %s", signer)
+	} else if !verifyAuditSignature(key, record) {
+		issue("bad_signature", "signature does not verify against %s's key %q", signer, record.KeyID)
+	}
+	return issues, nil
+}
+
+// auditSigners lists the engines whose chains belong to a task: every
+// registered chain writer plus the task's delegator, delegatee and chain
+// members, so a chain is still checked if the writer list loses it.
+func (e *Engine) auditSigners(taskID string) ([]string, error) {
+	writers, err := e.shardWriters(DomainMonitoring, taskID, "audit_head")
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool)
+	for _, w := range writers {
+		set[w] = true
+	}
+	task, err := e.GetTask(taskID)
+	if err != nil && !isNotFound(err) {
+		return nil, fmt.Errorf("get task %s: %w", taskID, err)
+	}
+	if task != nil {
+		set[task.DelegatorID] = true
+		set[task.DelegateeID] = true
+		for _, link := range task.DelegationChain {
+			set[link.DelegatorID] = true
+			set[link.DelegateeID] = true
+		}
+	}
+	delete(set, "")
+	
+	signers := make([]string, 0, len(set))
+	for id := range set {
+		signers = append(signers, id)
+	}
+	sort.Strings(signers)
+	return signers, nil
+}
+
+// auditHead returns a signer's chain head for the task, and whether it exists.
+// A chain that was never started has a zero head; read failures are returned.
+func (e *Engine) auditHead(taskID, signer string) (t.AuditHead, bool, error) {
+	var head t.AuditHead
+	found, err := e.retrieveJSON(DomainMonitoring, taskID, "audit_head_"+signer, &head)
+	if err != nil {
+		return head, false, fmt.Errorf("audit head of %s for %s: %w", signer, taskID, err)
+	}
+	return head, found, nil
+}
+
+func (e *Engine) getAuditRecord(taskID, signer string, seq uint64) (*t.AuditRecord, error) {
+	data, err := e.retrieveData(DomainMonitoring, taskID, auditAspect(signer, seq))
+	if err != nil {
+		return nil, err
+	}
+	var record t.AuditRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("unmarshal audit record %s/%d: %w", signer, seq, err)
+	}
+	return &record, nil
+}
+
+// signerKey looks up the public key a signer published under keyID. Records
+// from before key IDs were pinned fall back to the current key.
+func (e *Engine) signerKey(agentID, keyID string) ed25519.PublicKey {
+	agent, err := e.GetAgent(agentID)
+	if err != nil {
+		return nil
+	}
+	encoded := agent.SigningKey
+	if keyID != "" {
+		encoded = agent.SigningKeys[keyID]
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil
+	}
+	if keyID != "" && auditKeyID(key) != keyID {
+		return nil // Published under the wrong ID
+	}
+	return ed25519.PublicKey(key)
+}
+
+// auditKeyID identifies a signing key by the first 16 hex digits of its SHA-256.
+func auditKeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// auditHash is the hex SHA-256 of the record with Hash and Signature cleared.
+func auditHash(record t.AuditRecord) (string, error) {
+	record.Hash = ""
+	record.Signature = ""
+	body, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("marshal audit record: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func verifyAuditSignature(key ed25519.PublicKey, record t.AuditRecord) bool {
+	if key == nil {
+		return false
+	}
+	digest, err := hex.DecodeString(record.Hash)
+	if err != nil {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(record.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key, digest, sig)
+}
+
+func auditAspect(signer string, seq uint64) string {
+	return fmt.Sprintf("audit_%s_%d", signer, seq)
+}
+
+// short abbreviates a hash for issue messages.
+func short(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+	
+	"github.com/dataparency-dev/AI-delegation/types"
+)
+
+func TestAuditHash(t *testing.T) {
+	record := types.AuditRecord{
+		TaskID:     "t1",
+		Sequence:   1,
+		Kind:       AuditMonitorEvent,
+		RecordID:   "ev1",
+		Payload:    json.RawMessage(`{"progress":0.5}`),
+		SignerID:   "engine-a",
+		RecordedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	base, err := auditHash(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(base) != 64 {
+		t.Fatalf("auditHash() = %q, want 64 hex digits", base)
+	}
+	
+	tests := []struct {
+		name    string
+		edit    func(*types.AuditRecord)
+		changes bool
+	}{
+		{"hash field ignored", func(r *types.AuditRecord) { r.Hash = "abc" }, false},
+		{"signature ignored", func(r *types.AuditRecord) { r.Signature = "sig" }, false},
+		{"payload", func(r *types.AuditRecord) { r.Payload = json.RawMessage(`{"progress":0.9}`) }, true},
+		{"sequence", func(r *types.AuditRecord) { r.Sequence = 2 }, true},
+		{"previous hash", func(r *types.AuditRecord) { r.PrevHash = base }, true},
+		{"signer", func(r *types.AuditRecord) { r.SignerID = "engine-b" }, true},
+		{"time", func(r *types.AuditRecord) { r.RecordedAt = r.RecordedAt.Add(time.Second) }, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			edited := record
+			tc.edit(&edited)
+			got, err := auditHash(edited)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got != base) != tc.changes {
+				t.Errorf("hash changed = %v, want %v", got != base, tc.changes)
+			}
+		})
+	}
+}
+
+func TestAuditRecordIssues(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	// seal hashes and signs a record the way appendAudit does
+	seal := func(r types.AuditRecord) types.AuditRecord {
+		hash, err := auditHash(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Hash = hash
+		digest, _ := hex.DecodeString(hash)
+		r.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, digest))
+		return r
+	}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	first := seal(types.AuditRecord{TaskID: "t1", Sequence: 1, Kind: AuditTrigger, SignerID: "engine-a", RecordedAt: at})
+	second := seal(types.AuditRecord{TaskID: "t1", Sequence: 2, Kind: AuditTrigger, PrevHash: first.Hash, SignerID: "engine-a", RecordedAt: at})
+	
+	tests := []struct {
+		name     string
+		seq      uint64
+		record   types.AuditRecord
+		prevHash string
+		key      ed25519.PublicKey
+		want     []string
+	}{
+		{"sound first record", 1, first, "", pub, nil},
+		{"sound second record", 2, second, first.Hash, pub, nil},
+		{"previous record unreadable", 2, second, "", pub, nil},
+		{"modified payload", 2, func() types.AuditRecord {
+			r := second
+			r.Payload = json.RawMessage(`{"forged":true}`)
+			return r
+		}(), first.Hash, pub, []string{"modified"}}, // The signature covers the claimed hash
+		{"re-hashed and re-linked elsewhere", 2, seal(types.AuditRecord{
+			TaskID: "t1", Sequence: 2, Kind: AuditTrigger, PrevHash: "0000", SignerID: "engine-a", RecordedAt: at,
+		}), first.Hash, pub, []string{"broken_link"}},
+		{"first record with a link", 1, second, "", pub, []string{"reordered", "broken_link"}},
+		{"record from another task", 1, seal(types.AuditRecord{TaskID: "t2", Sequence: 1, SignerID: "engine-a", RecordedAt: at}), "", pub, []string{"reordered"}},
+		{"record from another signer", 1, seal(types.AuditRecord{TaskID: "t1", Sequence: 1, SignerID: "engine-b", RecordedAt: at}), "", pub, []string{"reordered"}},
+		{"wrong key", 1, first, "", otherPub, []string{"bad_signature"}},
+		{"no key", 1, first, "", nil, []string{"bad_signature"}},
+		{"unsigned", 1, func() types.AuditRecord {
+			r := first
+			r.Signature = ""
+			return r
+		}(), "", pub, []string{"unsigned"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			issues, err := auditRecordIssues("t1", "engine-a", tc.seq, tc.record, tc.prevHash, tc.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.Problem)
+				if issue.SignerID != "engine-a" || issue.Sequence != tc.seq {
+					t.Errorf("issue %+v not attributed to engine-a/%d", issue, tc.seq)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("issues = %v, want %v", issues, tc.want)
+			}
+		})
+	}
+}
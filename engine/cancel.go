@@ -0,0 +1,182 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+	nc "github.com/dataparency-dev/natsclient"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// CASCADING CANCELLATION (Sections 4.4, 4.7)
+// Cancelling a task cancels its whole subtree, leaves first: each delegatee is
+// told on its agent channel, contracts are terminated, permissions and DCTs
+// revoked, and the task's bidding and monitoring channels closed. Every
+// cancellation is written to the monitoring audit log.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// CancelTask cancels taskID and every descendant. Verified or already-cancelled
+// tasks are left as they are but their descendants are still visited. A task
+// that cannot be read or updated does not stop the rest of the tree: the
+// record is stored either way and the failures are returned joined.
+func (e *Engine) CancelTask(taskID, reason string) (_ *t.TaskCancellation, err error) {
+	_, done := e.traceTask(taskID, "engine.CancelTask")
+	defer done(&err)
+	
+	record := &t.TaskCancellation{
+		RootTaskID:  taskID,
+		Reason:      reason,
+		CancelledBy: e.SelfID,
+		CancelledAt: time.Now(),
+	}
+	cancelErr := e.cancelSubtree(taskID, record, make(map[string]bool))
+	
+	body, err := json.Marshal(record)
+	if err != nil {
+		return record, errors.Join(cancelErr, err)
+	}
+	if err := e.storeData(DomainTasks, taskID, "cancellation", body); err != nil {
+		return record, errors.Join(cancelErr, fmt.Errorf("store cancellation: %w", err))
+	}
+	
+	log.Printf("CANCELLED %d tasks under %s: %s", len(record.Cancelled), taskID, reason)
+	return record, cancelErr
+}
+
+// cancelSubtree cancels children before their parent so no parent is marked
+// cancelled while its children can still report into it.
+func (e *Engine) cancelSubtree(taskID string, record *t.TaskCancellation, seen map[string]bool) error {
+	if seen[taskID] {
+		return nil
+	}
+	seen[taskID] = true
+	
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return fmt.Errorf("get task %s: %w", taskID, err)
+	}
+	var errs []error
+	for _, childID := range task.SubTaskIDs {
+		errs = append(errs, e.cancelSubtree(childID, record, seen))
+	}
+	
+	if task.Status == t.TaskVerified || task.Status == t.TaskCancelled {
+		record.Skipped = append(record.Skipped, taskID)
+		return errors.Join(errs...)
+	}
+	
+	prevStatus := task.Status
+	task.Status = t.TaskCancelled
+	if err := e.UpdateTask(*task); err != nil {
+		return errors.Join(append(errs, fmt.Errorf("cancel task %s: %w", taskID, err))...)
+	}
+	record.Cancelled = append(record.Cancelled, taskID)
+	
+	if task.DelegateeID != "" {
+		e.notifyCancellation(task, record)
+	}
+	if task.ContractID != "" {
+		if err := e.terminateContract(task.ContractID); err != nil {
+			log.Printf("Terminate contract %s: %v", task.ContractID, err)
+		}
+	}
+	// A task assigned without a contract still granted its own permissions
+	if task.DelegateeID != "" {
+		e.revokeTaskPermissions(task)
+		if !isTerminal(prevStatus) {
+			if err := e.adjustAgentLoad(task.DelegateeID, -1); err != nil {
+				log.Printf("Release load of %s: %v", task.DelegateeID, err)
+			}
+		}
+	}
+	if _, err := e.RevokeTaskTokens(taskID); err != nil {
+		log.Printf("Revoke tokens for %s: %v", taskID, err)
+	}
+	
+	// Audit entry goes out before the monitoring channel is closed so live
+	// subscribers see it
+	err = e.EmitMonitorEvent(t.MonitorEvent{
+		EventID:   fmt.Sprintf("cancel_%s_%d", taskID, record.CancelledAt.UnixNano()),
+		TaskID:    taskID,
+		AgentID:   e.SelfID,
+		EventType: t.EventTaskCancelled,
+		Severity:  task.Criticality,
+		Message: fmt.Sprintf("cancelled from %s as part of %s: %s",
+			prevStatus, record.RootTaskID, record.Reason),
+	})
+	if err != nil {
+		log.Printf("Audit cancellation of %s: %v", taskID, err)
+	}
+	
+	e.closeChannel(fmt.Sprintf("bid_%s", taskID))
+	e.closeChannel(fmt.Sprintf("monitor_%s", taskID))
+	return errors.Join(errs...)
+}
+
+// revokeTaskPermissions revokes the task's own permissions from its delegatee,
+// skipping those its contract already covered.
+func (e *Engine) revokeTaskPermissions(task *t.TaskSpec) {
+	covered := make(map[string]bool)
+	if task.ContractID != "" {
+		if contract, err := e.GetContract(task.ContractID); err == nil {
+			for _, perm := range contract.Permissions {
+				covered[perm.Resource] = true
+			}
+		}
+	}
+	for _, perm := range task.Permissions {
+		if covered[perm.Resource] {
+			continue
+		}
+		covered[perm.Resource] = true
+		if err := e.RevokePermission(task.DelegateeID, perm.Resource); err != nil {
+			log.Printf("Revoke %s from %s: %v", perm.Resource, task.DelegateeID, err)
+		}
+	}
+}
+
+// notifyCancellation tells a task's delegatee to stop work.
+func (e *Engine) notifyCancellation(task *t.TaskSpec, record *t.TaskCancellation) {
+	channelName := agentChannelName(task.TaskID, task.DelegatorID, task.DelegateeID)
+	if err := e.publishAgentMessage(channelName, task.TaskID, t.MsgTaskCancelled, record); err != nil {
+		log.Printf("Notify %s of cancellation of %s: %v", task.DelegateeID, task.TaskID, err)
+	}
+}
+
+// terminateContract ends an active contract and revokes the permissions it granted.
+func (e *Engine) terminateContract(contractID string) error {
+	contract, err := e.GetContract(contractID)
+	if err != nil {
+		return err
+	}
+	if contract.Status != t.ContractActive && contract.Status != t.ContractDraft {
+		return nil
+	}
+	
+	contract.Status = t.ContractTerminated
+	if err := e.storeContract(contract); err != nil {
+		return err
+	}
+	for _, perm := range contract.Permissions {
+		if err := e.RevokePermission(contract.DelegateeID, perm.Resource); err != nil {
+			log.Printf("Revoke %s from %s: %v", perm.Resource, contract.DelegateeID, err)
+		}
+	}
+	return nil
+}
+
+// closeChannel removes a secure channel's RDID so it can no longer be
+// resolved for publishing or subscribing. Missing channels are ignored.
+func (e *Engine) closeChannel(channelName string) {
+	if rdid, _ := nc.RelationRetrieve(e.Server, channelName, e.Token); rdid == "" {
+		return
+	}
+	if _, status := nc.RelationRemove(e.Server, channelName, e.Token); status != http.StatusOK {
+		log.Printf("Close channel %s (status %d)", channelName, status)
+	}
+}
@@ -0,0 +1,424 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+	"github.com/nats-io/nats.go"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// CHECKPOINTS & RESUME (Section 4.4)
+// Delegatees store partial work as checkpoint artifacts. The delegatee's and
+// the delegator's engines both record checkpoints, so each keeps its own
+// numbering and index shard, and verdicts are stored by the verifier:
+//   Tasks/{id}/checkpoint_{writer}_{n}     → Checkpoint with artifact
+//   Tasks/{id}/checkpoints_{writer}        → []Checkpoint without artifacts (index)
+//   Tasks/{id}/checkpoint_verdicts_{engine} → []CheckpointVerdict given by that engine
+//   Tasks/{id}/resume                      → Checkpoint handed to the current delegatee
+// When a task is re-allocated, the latest verified checkpoint goes to the new
+// delegatee so it continues from there instead of starting over.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// SubmitCheckpoint stores a checkpoint for a task from its current delegatee
+// and emits a CHECKPOINT_REACHED event. The checkpoint starts unverified.
+func (e *Engine) SubmitCheckpoint(taskID string, artifact []byte, progress float64, metadata map[string]string) (*t.Checkpoint, error) {
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.DelegateeID != e.SelfID {
+		return nil, fmt.Errorf("%s is not the delegatee of task %s", e.SelfID, taskID)
+	}
+	return e.recordCheckpoint(taskID, e.SelfID, artifact, progress, metadata)
+}
+
+// recordCheckpoint stores the next checkpoint for a task on behalf of agentID.
+func (e *Engine) recordCheckpoint(taskID, agentID string, artifact []byte, progress float64, metadata map[string]string) (*t.Checkpoint, error) {
+	sum := sha256.Sum256(artifact)
+	cp := &t.Checkpoint{
+		TaskID:       taskID,
+		AgentID:      agentID,
+		Progress:     progress,
+		Artifact:     artifact,
+		ArtifactHash: hex.EncodeToString(sum[:]),
+		Metadata:     metadata,
+		CreatedAt:    time.Now(),
+	}
+	if err := e.appendCheckpoint(cp); err != nil {
+		return nil, err
+	}
+	
+	err := e.EmitMonitorEvent(t.MonitorEvent{
+		EventID:   fmt.Sprintf("checkpoint_%s_%s_%d", taskID, cp.WriterID, cp.Sequence),
+		TaskID:    taskID,
+		AgentID:   agentID,
+		EventType: t.EventCheckpoint,
+		Severity:  t.CriticalityLow,
+		Progress:  progress,
+		Message:   fmt.Sprintf("checkpoint %d stored (%d bytes)", cp.Sequence, len(artifact)),
+	})
+	if err != nil {
+		log.Printf("Checkpoint event for %s: %v", taskID, err)
+	}
+	
+	log.Printf("Checkpoint %d for task %s from %s at %.0f%%", cp.Sequence, taskID, agentID, progress*100)
+	return cp, nil
+}
+
+// appendCheckpoint numbers cp after the highest checkpoint this engine has
+// stored for the task and adds it to this engine's index shard. No other
+// engine writes that shard, so serializing here keeps sequences unique.
+func (e *Engine) appendCheckpoint(cp *t.Checkpoint) error {
+	e.checkpointMu.Lock()
+	defer e.checkpointMu.Unlock()
+	
+	if err := e.registerWriter(DomainTasks, cp.TaskID, "checkpoints"); err != nil {
+		return err
+	}
+	var index []t.Checkpoint
+	if _, err := e.retrieveJSON(DomainTasks, cp.TaskID, e.shardAspect("checkpoints"), &index); err != nil {
+		return fmt.Errorf("read checkpoint index for %s: %w", cp.TaskID, err)
+	}
+	cp.WriterID = e.SelfID
+	cp.Sequence = 1
+	if len(index) > 0 {
+		cp.Sequence = index[len(index)-1].Sequence + 1
+	}
+	
+	body, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := e.storeData(DomainTasks, cp.TaskID, checkpointAspect(cp.WriterID, cp.Sequence), body); err != nil {
+		return fmt.Errorf("store checkpoint %d: %w", cp.Sequence, err)
+	}
+	
+	entry := *cp
+	entry.Artifact = nil
+	body, err = json.Marshal(append(index, entry))
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainTasks, cp.TaskID, e.shardAspect("checkpoints"), body)
+}
+
+// VerifyCheckpoint records a verifier's verdict on a checkpoint in this
+// engine's verdict shard. Only verified checkpoints are eligible for resume.
+func (e *Engine) VerifyCheckpoint(taskID, writerID string, sequence int, verifierID string, passed bool) error {
+	e.checkpointMu.Lock()
+	defer e.checkpointMu.Unlock()
+	
+	if _, err := e.retrieveData(DomainTasks, taskID, checkpointAspect(writerID, sequence)); err != nil {
+		return fmt.Errorf("checkpoint %s/%d of %s: %w", writerID, sequence, taskID, err)
+	}
+	if err := e.registerWriter(DomainTasks, taskID, "checkpoint_verdicts"); err != nil {
+		return err
+	}
+	var verdicts []t.CheckpointVerdict
+	if _, err := e.retrieveJSON(DomainTasks, taskID, e.shardAspect("checkpoint_verdicts"), &verdicts); err != nil {
+		return fmt.Errorf("read checkpoint verdicts for %s: %w", taskID, err)
+	}
+	verdicts = append(verdicts, t.CheckpointVerdict{
+		TaskID:     taskID,
+		WriterID:   writerID,
+		Sequence:   sequence,
+		Passed:     passed,
+		VerifierID: verifierID,
+		VerifiedAt: time.Now(),
+	})
+	body, err := json.Marshal(verdicts)
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainTasks, taskID, e.shardAspect("checkpoint_verdicts"), body)
+}
+
+// RequestCheckpoint asks a task's delegatee to checkpoint now and stores the
+// checkpoint it replies with.
+func (e *Engine) RequestCheckpoint(taskID, reason string) (*t.Checkpoint, error) {
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return e.requestCheckpoint(task, reason, false)
+}
+
+// PauseTask asks the delegatee to checkpoint and hold the task, then marks it
+// paused. The task is paused even if no checkpoint arrives in time.
+func (e *Engine) PauseTask(taskID, reason string) error {
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	switch task.Status {
+	case t.TaskAssigned, t.TaskInProgress, t.TaskCheckpoint:
+	default:
+		return fmt.Errorf("task %s is %s; only running tasks can be paused", taskID, task.Status)
+	}
+	
+	if _, err := e.requestCheckpoint(task, reason, true); err != nil {
+		log.Printf("No checkpoint from %s for paused task %s: %v", task.DelegateeID, taskID, err)
+	}
+	task.Status = t.TaskPaused
+	if err := e.UpdateTask(*task); err != nil {
+		return err
+	}
+	log.Printf("Task %s PAUSED on %s: %s", taskID, task.DelegateeID, reason)
+	return nil
+}
+
+// ResumeTask lets the delegatee of a paused task carry on.
+func (e *Engine) ResumeTask(taskID string) error {
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	if task.Status != t.TaskPaused {
+		return fmt.Errorf("task %s is %s, not paused", taskID, task.Status)
+	}
+	
+	channelName, err := e.SetupAgentChannel(taskID, task.DelegateeID)
+	if err != nil {
+		return fmt.Errorf("agent channel for %s: %w", task.DelegateeID, err)
+	}
+	if err := e.publishAgentMessage(channelName, taskID, t.MsgTaskResumed, task); err != nil {
+		return err
+	}
+	task.Status = t.TaskInProgress
+	if err := e.UpdateTask(*task); err != nil {
+		return err
+	}
+	log.Printf("Task %s resumed on %s", taskID, task.DelegateeID)
+	return nil
+}
+
+// requestCheckpoint sends a checkpoint request to the delegatee and stores the
+// checkpoint it replies with.
+func (e *Engine) requestCheckpoint(task *t.TaskSpec, reason string, pause bool) (*t.Checkpoint, error) {
+	if task.DelegateeID == "" {
+		return nil, fmt.Errorf("task %s has no delegatee to checkpoint", task.TaskID)
+	}
+	channelName, err := e.SetupAgentChannel(task.TaskID, task.DelegateeID)
+	if err != nil {
+		return nil, err
+	}
+	req := t.CheckpointRequest{
+		TaskID:   task.TaskID,
+		Reason:   reason,
+		Pause:    pause,
+		IssuedAt: time.Now(),
+	}
+	data, err := e.requestAgentMessage(channelName, task.TaskID, t.MsgCheckpointRequest, req, e.FailoverTimeout)
+	if err != nil {
+		return nil, err
+	}
+	
+	var reply t.Checkpoint
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, fmt.Errorf("unmarshal requested checkpoint: %w", err)
+	}
+	return e.recordCheckpoint(task.TaskID, task.DelegateeID, reply.Artifact, reply.Progress, reply.Metadata)
+}
+
+// ServeCheckpointRequests is the delegatee's side of RequestCheckpoint and
+// PauseTask. It listens on the agent channel for the given task and answers
+// each request with the checkpoint snapshot returns; when req.Pause is set the
+// delegatee is expected to hold the task until it is resumed.
+func (e *Engine) ServeCheckpointRequests(taskID, delegatorID string, snapshot func(t.CheckpointRequest) t.Checkpoint) error {
+	channelName := agentChannelName(taskID, delegatorID, e.SelfID)
+	return e.subscribeAgentChannel(channelName, "checkpoint", func(env t.AgentMessage, msg *nats.Msg) {
+		if env.Type != t.MsgCheckpointRequest {
+			return
+		}
+		var req t.CheckpointRequest
+		if err := json.Unmarshal(env.Payload, &req); err != nil {
+			log.Printf("Malformed checkpoint request on %s: %v", channelName, err)
+			return
+		}
+		cp := snapshot(req)
+		cp.TaskID = req.TaskID
+		cp.AgentID = e.SelfID
+		body, _ := json.Marshal(cp)
+		if err := msg.Respond(body); err != nil {
+			log.Printf("Checkpoint reply on %s: %v", channelName, err)
+		}
+	})
+}
+
+// ServeTaskResumed is the delegatee's side of ResumeTask. It listens on the
+// agent channel for the given task and calls resume with the task once the
+// delegator lets it carry on.
+func (e *Engine) ServeTaskResumed(taskID, delegatorID string, resume func(t.TaskSpec)) error {
+	channelName := agentChannelName(taskID, delegatorID, e.SelfID)
+	return e.subscribeAgentChannel(channelName, "resume", func(env t.AgentMessage, msg *nats.Msg) {
+		if env.Type != t.MsgTaskResumed {
+			return
+		}
+		var task t.TaskSpec
+		if err := json.Unmarshal(env.Payload, &task); err != nil {
+			log.Printf("Malformed resume notice on %s: %v", channelName, err)
+			return
+		}
+		resume(task)
+	})
+}
+
+// GetCheckpoint retrieves one checkpoint, including its artifact, with its
+// latest verdict applied.
+func (e *Engine) GetCheckpoint(taskID, writerID string, sequence int) (*t.Checkpoint, error) {
+	var cp t.Checkpoint
+	found, err := e.retrieveJSON(DomainTasks, taskID, checkpointAspect(writerID, sequence), &cp)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("checkpoint %s/%d of %s: %w", writerID, sequence, taskID, ErrNotFound)
+	}
+	verdicts, err := e.checkpointVerdicts(taskID)
+	if err != nil {
+		return nil, err
+	}
+	applyVerdict(&cp, verdicts)
+	return &cp, nil
+}
+
+// ListCheckpoints returns a task's checkpoints from every writer, oldest first
+// and without artifacts, with their latest verdicts applied. A task with no
+// checkpoints yet has an empty list; read errors are returned.
+func (e *Engine) ListCheckpoints(taskID string) ([]t.Checkpoint, error) {
+	writers, err := e.shardWriters(DomainTasks, taskID, "checkpoints")
+	if err != nil {
+		return nil, err
+	}
+	var all []t.Checkpoint
+	for _, w := range writers {
+		var index []t.Checkpoint
+		if _, err := e.retrieveJSON(DomainTasks, taskID, "checkpoints_"+w, &index); err != nil {
+			return nil, fmt.Errorf("read checkpoint index %s for %s: %w", w, taskID, err)
+		}
+		all = append(all, index...)
+	}
+	verdicts, err := e.checkpointVerdicts(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range all {
+		applyVerdict(&all[i], verdicts)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].CreatedAt.Equal(all[j].CreatedAt) {
+			return all[i].CreatedAt.Before(all[j].CreatedAt)
+		}
+		if all[i].WriterID != all[j].WriterID {
+			return all[i].WriterID < all[j].WriterID
+		}
+		return all[i].Sequence < all[j].Sequence
+	})
+	return all, nil
+}
+
+// checkpointVerdicts merges every verifier's verdict shard, keeping the
+// latest verdict per checkpoint.
+func (e *Engine) checkpointVerdicts(taskID string) (map[string]t.CheckpointVerdict, error) {
+	writers, err := e.shardWriters(DomainTasks, taskID, "checkpoint_verdicts")
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]t.CheckpointVerdict)
+	for _, w := range writers {
+		var verdicts []t.CheckpointVerdict
+		if _, err := e.retrieveJSON(DomainTasks, taskID, "checkpoint_verdicts_"+w, &verdicts); err != nil {
+			return nil, fmt.Errorf("read checkpoint verdicts %s for %s: %w", w, taskID, err)
+		}
+		for _, v := range verdicts {
+			key := checkpointAspect(v.WriterID, v.Sequence)
+			if cur, ok := latest[key]; !ok || v.VerifiedAt.After(cur.VerifiedAt) {
+				latest[key] = v
+			}
+		}
+	}
+	return latest, nil
+}
+
+// applyVerdict fills cp's verification state from its latest verdict, if any.
+func applyVerdict(cp *t.Checkpoint, verdicts map[string]t.CheckpointVerdict) {
+	v, ok := verdicts[checkpointAspect(cp.WriterID, cp.Sequence)]
+	if !ok {
+		return
+	}
+	at := v.VerifiedAt
+	cp.Verified = v.Passed
+	cp.Rejected = !v.Passed
+	cp.VerifiedBy = v.VerifierID
+	cp.VerifiedAt = &at
+}
+
+// LatestVerifiedCheckpoint returns the most recent verified checkpoint with
+// its artifact, or nil if no checkpoint has been verified.
+func (e *Engine) LatestVerifiedCheckpoint(taskID string) (*t.Checkpoint, error) {
+	index, err := e.ListCheckpoints(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(index) - 1; i >= 0; i-- {
+		if index[i].Verified {
+			return e.GetCheckpoint(taskID, index[i].WriterID, index[i].Sequence)
+		}
+	}
+	return nil, nil
+}
+
+// GetResumeCheckpoint returns the checkpoint the current delegatee should
+// resume from, or nil if the task starts from scratch. Read errors are returned.
+func (e *Engine) GetResumeCheckpoint(taskID string) (*t.Checkpoint, error) {
+	var cp t.Checkpoint
+	found, err := e.retrieveJSON(DomainTasks, taskID, "resume", &cp)
+	if err != nil {
+		return nil, fmt.Errorf("read resume checkpoint for %s: %w", taskID, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+// handOffCheckpoint gives the task's new delegatee the latest verified
+// checkpoint: it is staged under Tasks/{id}/resume and sent on the agent channel.
+func (e *Engine) handOffCheckpoint(task *t.TaskSpec) error {
+	cp, err := e.LatestVerifiedCheckpoint(task.TaskID)
+	if err != nil || cp == nil {
+		return err
+	}
+	
+	body, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := e.storeData(DomainTasks, task.TaskID, "resume", body); err != nil {
+		return fmt.Errorf("stage resume checkpoint: %w", err)
+	}
+	
+	channelName, err := e.SetupAgentChannel(task.TaskID, task.DelegateeID)
+	if err != nil {
+		return fmt.Errorf("agent channel for %s: %w", task.DelegateeID, err)
+	}
+	if err := e.publishAgentMessage(channelName, task.TaskID, t.MsgResumeCheckpoint, cp); err != nil {
+		return err
+	}
+	
+	log.Printf("Task %s resumes at checkpoint %d (%.0f%%) for %s",
+		task.TaskID, cp.Sequence, cp.Progress*100, task.DelegateeID)
+	return nil
+}
+
+// checkpointAspect is where checkpoint n stored by writer lives.
+func checkpointAspect(writerID string, sequence int) string {
+	return fmt.Sprintf("checkpoint_%s_%d", writerID, sequence)
+}
@@ -0,0 +1,178 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// DECOMPOSITION CONSISTENCY (Section 4.1)
+// A decomposition must stay inside its parent's envelope: budget, deadline,
+// capabilities, criticality and permissions. Every rule is evaluated and all
+// violations are returned together so the caller can fix them in one pass.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// DecompositionError is returned by DecomposeTask when the consistency checks fail.
+type DecompositionError struct {
+	Report *t.DecompositionReport
+}
+
+func (e *DecompositionError) Error() string {
+	msgs := make([]string, 0, len(e.Report.Violations))
+	for _, v := range e.Report.Violations {
+		msgs = append(msgs, fmt.Sprintf("[%s] %s", v.Check, v.Message))
+	}
+	return fmt.Sprintf("decomposition of %s has %d violations: %s",
+		e.Report.ParentTaskID, len(e.Report.Violations), strings.Join(msgs, "; "))
+}
+
+// ValidateDecomposition checks proposed sub-tasks against a stored parent
+// without creating anything, so a decomposition can be reviewed before it is applied.
+func (e *Engine) ValidateDecomposition(parentID string, subTasks []t.TaskSpec) (*t.DecompositionReport, error) {
+	parent, err := e.GetTask(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("get parent task: %w", err)
+	}
+	subTasks = append([]t.TaskSpec(nil), subTasks...)
+	inheritDeadline(parent, subTasks)
+	return checkDecomposition(parent, subTasks), nil
+}
+
+// inheritDeadline gives sub-tasks without a deadline the parent's, as
+// sub-delegation does; a missing budget is left for the checks to flag.
+func inheritDeadline(parent *t.TaskSpec, subTasks []t.TaskSpec) {
+	for i := range subTasks {
+		if subTasks[i].Deadline == nil && parent.Deadline != nil {
+			deadline := *parent.Deadline
+			subTasks[i].Deadline = &deadline
+		}
+	}
+}
+
+// checkDecomposition runs every consistency rule and collects the violations.
+func checkDecomposition(parent *t.TaskSpec, subTasks []t.TaskSpec) *t.DecompositionReport {
+	report := &t.DecompositionReport{
+		ParentTaskID: parent.TaskID,
+		SubTasks:     len(subTasks),
+		CheckedAt:    time.Now(),
+	}
+	add := func(check t.DecompositionCheck, subID, format string, args ...interface{}) {
+		report.Violations = append(report.Violations, t.DecompositionViolation{
+			Check:     check,
+			SubTaskID: subID,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+	
+	if err := ValidateDependencies(subTasks); err != nil {
+		add(t.CheckDependencies, "", "%v", err)
+	}
+	
+	var total float64
+	covered := make(map[string]bool)
+	for _, sub := range subTasks {
+		// Contract-first: sub-tasks that will not be decomposed further must be verifiable
+		if sub.Verifiability < 0.3 && !sub.IsLeaf {
+			add(t.CheckVerifiability, sub.TaskID,
+				"verifiability %.2f is below 0.30; decompose further or add verification artifacts",
+				sub.Verifiability)
+		}
+		
+		// A zero budget or missing deadline means unbounded, which a bounded
+		// parent cannot hand out
+		total += sub.MaxBudget
+		if parent.MaxBudget > 0 && sub.MaxBudget <= 0 {
+			add(t.CheckBudget, sub.TaskID, "no budget set under parent budget %.2f", parent.MaxBudget)
+		}
+		
+		switch {
+		case parent.Deadline == nil:
+		case sub.Deadline == nil:
+			add(t.CheckDeadline, sub.TaskID, "no deadline set under parent deadline %s",
+				parent.Deadline.Format(time.RFC3339))
+		case sub.Deadline.After(*parent.Deadline):
+			add(t.CheckDeadline, sub.TaskID, "deadline %s is after parent deadline %s",
+				sub.Deadline.Format(time.RFC3339), parent.Deadline.Format(time.RFC3339))
+		}
+		
+		if sub.Criticality.Rank() < parent.Criticality.Rank() && strings.TrimSpace(sub.CriticalityRationale) == "" {
+			add(t.CheckCriticality, sub.TaskID, "criticality %q is below parent's %q without a rationale",
+				sub.Criticality, parent.Criticality)
+		}
+		
+		for _, perm := range sub.Permissions {
+			if !permissionCovered(perm, parent.Permissions) {
+				add(t.CheckPermissions, sub.TaskID, "permission %s %v (scope %q) exceeds the parent's grants",
+					perm.Resource, perm.Operations, perm.Scope)
+			}
+		}
+		
+		for _, capability := range sub.RequiredCapabilities {
+			covered[capability] = true
+		}
+	}
+	
+	if parent.MaxBudget > 0 && total > parent.MaxBudget {
+		add(t.CheckBudget, "", "sub-task budgets total %.2f, exceeding parent budget %.2f",
+			total, parent.MaxBudget)
+	}
+	
+	var missing []string
+	for _, capability := range parent.RequiredCapabilities {
+		if !covered[capability] {
+			missing = append(missing, capability)
+		}
+	}
+	if len(missing) > 0 {
+		add(t.CheckCapabilities, "", "no sub-task requires %v", missing)
+	}
+	return report
+}
+
+// permissionCovered reports whether some parent grant includes the child
+// permission: same resource, a superset of operations, an equal or wider scope,
+// and an expiry no earlier than the child's.
+func permissionCovered(child t.Permission, grants []t.Permission) bool {
+	for _, grant := range grants {
+		if grant.Resource != child.Resource {
+			continue
+		}
+		if !scopeCovered(child.Scope, grant.Scope) {
+			continue
+		}
+		if grant.ExpiresAt != nil && (child.ExpiresAt == nil || child.ExpiresAt.After(*grant.ExpiresAt)) {
+			continue
+		}
+		ops := make(map[string]bool, len(grant.Operations))
+		for _, op := range grant.Operations {
+			ops[op] = true
+		}
+		allowed := true
+		for _, op := range child.Operations {
+			if !ops[op] {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeCovered reports whether scope lies within grant on path-segment
+// boundaries, so "/data" covers "/data" and "/data/raw" but not "/database".
+// An empty grant covers every scope.
+func scopeCovered(scope, grant string) bool {
+	if grant == "" || scope == grant {
+		return true
+	}
+	if !strings.HasPrefix(scope, grant) {
+		return false
+	}
+	return strings.HasSuffix(grant, "/") || scope[len(grant)] == '/'
+}
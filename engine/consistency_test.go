@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	
+	"github.com/dataparency-dev/AI-delegation/types"
+)
+
+func TestScopeCovered(t *testing.T) {
+	tests := []struct {
+		scope, grant string
+		want         bool
+	}{
+		{"/data", "/data", true},
+		{"/data/raw", "/data", true},
+		{"/data/raw", "/data/", true},
+		{"/database", "/data", false},
+		{"/dat", "/data", false},
+		{"", "/data", false},
+		{"/anything", "", true},
+	}
+	for _, tc := range tests {
+		if got := scopeCovered(tc.scope, tc.grant); got != tc.want {
+			t.Errorf("scopeCovered(%q, %q) = %v, want %v", tc.scope, tc.grant, got, tc.want)
+		}
+	}
+}
+
+func TestPermissionCovered(t *testing.T) {
+	now := time.Now()
+	soon, later := now.Add(time.Hour), now.Add(2*time.Hour)
+	grants := []types.Permission{
+		{Resource: "api", Operations: []string{"read", "write"}, Scope: "/data"},
+		{Resource: "db", Operations: []string{"read"}, ExpiresAt: &soon},
+	}
+	tests := []struct {
+		name  string
+		child types.Permission
+		want  bool
+	}{
+		{"same grant", types.Permission{Resource: "api", Operations: []string{"read", "write"}, Scope: "/data"}, true},
+		{"fewer operations", types.Permission{Resource: "api", Operations: []string{"read"}, Scope: "/data"}, true},
+		{"narrower scope", types.Permission{Resource: "api", Operations: []string{"read"}, Scope: "/data/raw"}, true},
+		{"extra operation", types.Permission{Resource: "api", Operations: []string{"execute"}, Scope: "/data"}, false},
+		{"sibling scope", types.Permission{Resource: "api", Operations: []string{"read"}, Scope: "/database"}, false},
+		{"wider scope", types.Permission{Resource: "api", Operations: []string{"read"}}, false},
+		{"other resource", types.Permission{Resource: "tool", Operations: []string{"read"}}, false},
+		{"expires with the grant", types.Permission{Resource: "db", Operations: []string{"read"}, ExpiresAt: &soon}, true},
+		{"outlives the grant", types.Permission{Resource: "db", Operations: []string{"read"}, ExpiresAt: &later}, false},
+		{"never expires under an expiring grant", types.Permission{Resource: "db", Operations: []string{"read"}}, false},
+		{"expires under a lasting grant", types.Permission{Resource: "api", Operations: []string{"read"}, Scope: "/data", ExpiresAt: &soon}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := permissionCovered(tc.child, grants); got != tc.want {
+				t.Errorf("permissionCovered() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheckDecomposition(t *testing.T) {
+	deadline := time.Now().Add(24 * time.Hour)
+	early, late := deadline.Add(-time.Hour), deadline.Add(time.Hour)
+	parent := &types.TaskSpec{
+		TaskID:               "p",
+		MaxBudget:            100,
+		Deadline:             &deadline,
+		Criticality:          types.CriticalityHigh,
+		RequiredCapabilities: []string{"x"},
+		Permissions:          []types.Permission{{Resource: "api", Operations: []string{"read", "write"}, Scope: "/data"}},
+	}
+	sub := func(id string, edit func(*types.TaskSpec)) types.TaskSpec {
+		s := types.TaskSpec{
+			TaskID:               id,
+			MaxBudget:            40,
+			Deadline:             &early,
+			Criticality:          types.CriticalityHigh,
+			Verifiability:        0.9,
+			IsLeaf:               true,
+			RequiredCapabilities: []string{"x"},
+		}
+		if edit != nil {
+			edit(&s)
+		}
+		return s
+	}
+	tests := []struct {
+		name string
+		subs []types.TaskSpec
+		want []types.DecompositionCheck
+	}{
+		{"consistent", []types.TaskSpec{sub("a", nil), sub("b", nil)}, nil},
+		{"budgets exceed the parent", []types.TaskSpec{
+			sub("a", func(s *types.TaskSpec) { s.MaxBudget = 60 }),
+			sub("b", func(s *types.TaskSpec) { s.MaxBudget = 60 }),
+		}, []types.DecompositionCheck{types.CheckBudget}},
+		{"unbounded budget", []types.TaskSpec{sub("a", func(s *types.TaskSpec) { s.MaxBudget = 0 })},
+			[]types.DecompositionCheck{types.CheckBudget}},
+		{"deadline after the parent's", []types.TaskSpec{sub("a", func(s *types.TaskSpec) { s.Deadline = &late })},
+			[]types.DecompositionCheck{types.CheckDeadline}},
+		{"no deadline", []types.TaskSpec{sub("a", func(s *types.TaskSpec) { s.Deadline = nil })},
+			[]types.DecompositionCheck{types.CheckDeadline}},
+		{"unjustified downgrade", []types.TaskSpec{sub("a", func(s *types.TaskSpec) { s.Criticality = types.CriticalityLow })},
+			[]types.DecompositionCheck{types.CheckCriticality}},
+		{"justified downgrade", []types.TaskSpec{sub("a", func(s *types.TaskSpec) {
+			s.Criticality = types.CriticalityLow
+			s.CriticalityRationale = "read-only lookup"
+		})}, nil},
+		{"permission beyond the parent's", []types.TaskSpec{sub("a", func(s *types.TaskSpec) {
+			s.Permissions = []types.Permission{{Resource: "api", Operations: []string{"write"}, Scope: "/database"}}
+		})}, []types.DecompositionCheck{types.CheckPermissions}},
+		{"capability not covered", []types.TaskSpec{sub("a", func(s *types.TaskSpec) { s.RequiredCapabilities = []string{"y"} })},
+			[]types.DecompositionCheck{types.CheckCapabilities}},
+		{"unverifiable non-leaf", []types.TaskSpec{sub("a", func(s *types.TaskSpec) {
+			s.Verifiability = 0.1
+			s.IsLeaf = false
+		})}, []types.DecompositionCheck{types.CheckVerifiability}},
+		{"unverifiable leaf", []types.TaskSpec{sub("a", func(s *types.TaskSpec) { s.Verifiability = 0.1 })}, nil},
+		{"dependency cycle", []types.TaskSpec{
+			sub("a", func(s *types.TaskSpec) { s.DependsOn = []string{"b"} }),
+			sub("b", func(s *types.TaskSpec) { s.DependsOn = []string{"a"} }),
+		}, []types.DecompositionCheck{types.CheckDependencies}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report := checkDecomposition(parent, tc.subs)
+			var got []types.DecompositionCheck
+			for _, v := range report.Violations {
+				got = append(got, v.Check)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("violations = %v, want %v", report.Violations, tc.want)
+			}
+			if report.SubTasks != len(tc.subs) {
+				t.Errorf("SubTasks = %d, want %d", report.SubTasks, len(tc.subs))
+			}
+		})
+	}
+}
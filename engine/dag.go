@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// SUB-TASK DEPENDENCIES (Section 4.1)
+// DependsOn edges between siblings form a DAG. A sub-task is released to the
+// market only once every predecessor is verified, and starts with the
+// predecessors' result artifacts staged under Tasks/{id}/inputs.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// ValidateDependencies checks that every DependsOn edge names a sibling in the
+// same decomposition and that the edges contain no cycle.
+func ValidateDependencies(subTasks []t.TaskSpec) error {
+	deps := make(map[string][]string, len(subTasks))
+	for _, sub := range subTasks {
+		deps[sub.TaskID] = sub.DependsOn
+	}
+	for _, sub := range subTasks {
+		for _, dep := range sub.DependsOn {
+			if dep == sub.TaskID {
+				return fmt.Errorf("sub-task %s depends on itself", sub.TaskID)
+			}
+			if _, ok := deps[dep]; !ok {
+				return fmt.Errorf("sub-task %s depends on %s, which is not a sibling", sub.TaskID, dep)
+			}
+		}
+	}
+	
+	if cycle := findCycle(deps); cycle != nil {
+		return fmt.Errorf("dependency cycle: %v", cycle)
+	}
+	return nil
+}
+
+// findCycle returns the task IDs along a dependency cycle, or nil if the graph is acyclic.
+func findCycle(deps map[string][]string) []string {
+	const (
+		unvisited = iota
+		inStack
+		done
+	)
+	state := make(map[string]int, len(deps))
+	var stack []string
+	
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = inStack
+		stack = append(stack, id)
+		for _, dep := range deps[id] {
+			switch state[dep] {
+			case inStack:
+				for i, s := range stack {
+					if s == dep {
+						return append(append([]string{}, stack[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+		return nil
+	}
+	
+	// Deterministic order so the reported cycle is stable
+	ids := make([]string, 0, len(deps))
+	for id := range deps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// unmetDependencies lists the task's predecessors that are not yet verified.
+func (e *Engine) unmetDependencies(task t.TaskSpec) []string {
+	var waiting []string
+	for _, dep := range task.DependsOn {
+		pred, err := e.GetTask(dep)
+		if err != nil || pred.Status != t.TaskVerified {
+			waiting = append(waiting, dep)
+		}
+	}
+	return waiting
+}
+
+// PublishReadySubTasks publishes every pending sub-task of parentID whose
+// dependencies are all verified. One failing sibling does not hold back the
+// others: the IDs that were published are returned with every error joined.
+func (e *Engine) PublishReadySubTasks(parentID string) ([]string, error) {
+	parent, err := e.GetTask(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("get parent task: %w", err)
+	}
+	
+	var published []string
+	var errs []error
+	for _, subID := range parent.SubTaskIDs {
+		sub, err := e.GetTask(subID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("get sub-task %s: %w", subID, err))
+			continue
+		}
+		if sub.Status != t.TaskPending || len(e.unmetDependencies(*sub)) > 0 {
+			continue
+		}
+		if _, err := e.PublishTaskForBidding(*sub); err != nil {
+			errs = append(errs, fmt.Errorf("publish sub-task %s: %w", subID, err))
+			continue
+		}
+		published = append(published, subID)
+	}
+	
+	if len(published) > 0 {
+		log.Printf("Released %d ready sub-tasks of %s: %v", len(published), parentID, published)
+	}
+	return published, errors.Join(errs...)
+}
+
+// GetResultArtifact retrieves the result artifact a delegatee submitted for a task.
+func (e *Engine) GetResultArtifact(taskID string) ([]byte, error) {
+	return e.retrieveData(DomainTasks, taskID, "result_artifact")
+}
+
+// GetTaskInputs retrieves the predecessor artifacts staged for a task.
+func (e *Engine) GetTaskInputs(taskID string) ([]t.TaskInput, error) {
+	data, err := e.retrieveData(DomainTasks, taskID, "inputs")
+	if err != nil {
+		return nil, err
+	}
+	var inputs []t.TaskInput
+	if err := json.Unmarshal(data, &inputs); err != nil {
+		return nil, fmt.Errorf("unmarshal task inputs: %w", err)
+	}
+	return inputs, nil
+}
+
+// stageDependencyInputs copies each predecessor's result artifact to the task's inputs.
+func (e *Engine) stageDependencyInputs(task *t.TaskSpec) error {
+	now := time.Now()
+	inputs := make([]t.TaskInput, 0, len(task.DependsOn))
+	for _, dep := range task.DependsOn {
+		artifact, err := e.GetResultArtifact(dep)
+		if err != nil {
+			return fmt.Errorf("result artifact of %s: %w", dep, err)
+		}
+		inputs = append(inputs, t.TaskInput{FromTaskID: dep, Artifact: artifact, StagedAt: now})
+	}
+	
+	body, err := json.Marshal(inputs)
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainTasks, task.TaskID, "inputs", body)
+}
@@ -0,0 +1,29 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindCycle(t *testing.T) {
+	tests := []struct {
+		name string
+		deps map[string][]string
+		want []string
+	}{
+		{"empty", nil, nil},
+		{"chain", map[string][]string{"a": {"b"}, "b": {"c"}, "c": nil}, nil},
+		{"diamond", map[string][]string{"a": {"b", "c"}, "b": {"d"}, "c": {"d"}, "d": nil}, nil},
+		{"edge outside the graph", map[string][]string{"a": {"x"}}, nil},
+		{"self loop", map[string][]string{"a": {"a"}}, []string{"a", "a"}},
+		{"two tasks", map[string][]string{"a": {"b"}, "b": {"a"}}, []string{"a", "b", "a"}},
+		{"cycle below the entry", map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"b"}}, []string{"b", "c", "b"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := findCycle(tc.deps); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("findCycle() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
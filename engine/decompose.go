@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// AUTOMATIC DECOMPOSITION (Section 4.1)
+// A Decomposer proposes sub-tasks for a parent; the engine then runs them
+// through DecomposeTask so the usual contract-first and DAG checks still apply.
+// The decompose package ships a recipe-driven TemplateDecomposer; a planner
+// backed by a language model can satisfy the same interface.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// Decomposer proposes sub-tasks for a parent task. Implementations return
+// specs only; persistence and validation are left to the engine.
+type Decomposer interface {
+	Decompose(parent t.TaskSpec) ([]t.TaskSpec, error)
+}
+
+// AutoDecompose asks the engine's Decomposer to split a task and applies the
+// result with DecomposeTask.
+func (e *Engine) AutoDecompose(taskID string) (*t.TaskSpec, error) {
+	if e.Decomposer == nil {
+		return nil, fmt.Errorf("no decomposer configured")
+	}
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get task: %w", err)
+	}
+	if task.IsLeaf {
+		return nil, fmt.Errorf("task %s is a leaf and cannot be decomposed", taskID)
+	}
+	if len(task.SubTaskIDs) > 0 {
+		return nil, fmt.Errorf("task %s is already decomposed", taskID)
+	}
+	
+	subTasks, err := e.Decomposer.Decompose(*task)
+	if err != nil {
+		return nil, fmt.Errorf("decompose %s: %w", taskID, err)
+	}
+	if len(subTasks) == 0 {
+		return nil, fmt.Errorf("decomposer produced no sub-tasks for %s", taskID)
+	}
+	
+	log.Printf("Decomposer proposed %d sub-tasks for %s", len(subTasks), taskID)
+	return e.DecomposeTask(taskID, subTasks)
+}
@@ -8,14 +8,23 @@
 package engine
 
 import (
+	"context"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
 	"net/http"
+	"sync"
 	"time"
 	
+	"github.com/awgh/bencrypt/ecc"
 	t "github.com/dataparency-dev/AI-delegation/types"
 	nc "github.com/dataparency-dev/natsclient" // The uploaded natsclient package
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -26,6 +35,8 @@ const (
 	DomainMonitoring = "Monitoring"
 	DomainReputation = "Reputation"
 	DomainTriggers   = "Triggers"
+	DomainEvents     = "Events"
+	DomainPolicies   = "Policies"
 )
 
 // Engine is the central delegation orchestrator. It holds a reference to the
@@ -35,6 +46,46 @@ type Engine struct {
 	Server string      // NATS server topic for the D-DDN backend
 	Token  nc.APIToken // Authenticated session token
 	SelfID string      // This engine's agent identity
+	
+	FailoverTimeout    time.Duration       // How long a backup agent has to answer a failover offer
+	HeartbeatInterval  time.Duration       // How often agents are expected to send a heartbeat
+	MissedBeats        int                 // Heartbeats an agent may miss before it is marked offline
+	MaxDelegationDepth int                 // Delegatee hops allowed below the original delegator
+	MaxChainLength     int                 // Links allowed in a task's delegation chain
+	Preemption         t.PreemptionPolicy  // Whether higher-priority tasks may displace lower ones
+	Decomposer         Decomposer          // Strategy used by AutoDecompose; nil disables it
+	Detector           *AnomalyDetector    // Raises triggers from monitoring anomalies; nil disables it
+	Redactor           *Redactor           // Scrubs monitoring events before they leave this engine; nil disables it
+	SigningKey         ed25519.PrivateKey  // Signs audit records; set with SetSigningKey, nil leaves them unsigned
+	EncryptionKey      *ecc.KeyPair        // Opens events sealed to this engine; set with SetEncryptionKey
+	Tracer             trace.Tracer        // Spans for engine operations; nil uses the global OpenTelemetry provider
+	Metrics            *Metrics            // Prometheus collectors; set by EnableMetrics, nil disables them
+	Notifications      *NotificationRouter // Routes escalations and alerts to overseers; nil only logs them
+	
+	indexMu      sync.Mutex // Serializes read-modify-write of this engine's catalog shards
+	auditMu      sync.Mutex // Serializes appends to per-task audit chains
+	eventMu      sync.Mutex // Serializes appends to the domain event log
+	checkpointMu sync.Mutex // Serializes checkpoint numbering and index updates
+	listMu       sync.Mutex // Serializes appends to this engine's shards of shared lists
+	
+	policyMu sync.RWMutex      // Guards policy
+	policy   *t.ResponsePolicy // Adaptive response rules; set by PublishResponsePolicy or ActivateResponsePolicy, nil uses DefaultResponsePolicy
+	
+	traceMu sync.Mutex                   // Guards traces
+	traces  map[string][]context.Context // Open task spans, innermost last, by task ID
+	
+	reportMu sync.Mutex              // Guards batches and silent
+	batches  map[string]*reportBatch // Periodic-mode events awaiting their report, by task ID
+	silent   map[string]time.Time    // Last event time already flagged as a missed report, by task ID
+	
+	liveMu sync.RWMutex         // Guards live
+	live   map[string]*liveTask // Monitoring aggregator state by task ID
+	
+	beatMu   sync.Mutex           // Guards lastBeat
+	lastBeat map[string]time.Time // Last sign of life by agent ID
+	
+	writerMu sync.Mutex           // Guards writers
+	writers  map[string]time.Time // Confirmed shard registrations by domain/entity/aspect
 }
 
 // NewEngine connects to the NATS backend, authenticates, and returns a
@@ -53,9 +104,14 @@ func NewEngine(natsURL, serverTopic, user, password, selfID string) (*Engine, er
 	log.Printf("Delegation engine authenticated as %s", user)
 	
 	return &Engine{
-		Server: serverTopic,
-		Token:  token,
-		SelfID: selfID,
+		Server:             serverTopic,
+		Token:              token,
+		SelfID:             selfID,
+		FailoverTimeout:    5 * time.Second,
+		HeartbeatInterval:  DefaultHeartbeatInterval,
+		MissedBeats:        DefaultMissedBeats,
+		MaxDelegationDepth: DefaultMaxDelegationDepth,
+		MaxChainLength:     DefaultMaxChainLength,
 	}, nil
 }
 
@@ -106,6 +162,10 @@ func (e *Engine) RegisterAgent(profile t.AgentProfile) error {
 		return fmt.Errorf("store agent profile: %w", err)
 	}
 	
+	if err := e.indexAgentRole(profile); err != nil {
+		log.Printf("Index role for agent %s: %v", profile.AgentID, err)
+	}
+	
 	log.Printf("Agent registered: %s (%s, %s)", profile.AgentID, profile.Type, profile.Role)
 	return nil
 }
@@ -126,6 +186,11 @@ func (e *Engine) GetAgent(agentID string) (*t.AgentProfile, error) {
 // UpdateAgent modifies an existing agent profile.
 func (e *Engine) UpdateAgent(profile t.AgentProfile) error {
 	profile.LastSeenAt = time.Now()
+	return e.saveAgent(profile)
+}
+
+// saveAgent writes a profile as given, without touching LastSeenAt.
+func (e *Engine) saveAgent(profile t.AgentProfile) error {
 	body, err := json.Marshal(profile)
 	if err != nil {
 		return fmt.Errorf("marshal agent profile: %w", err)
@@ -136,7 +201,32 @@ func (e *Engine) UpdateAgent(profile t.AgentProfile) error {
 		return fmt.Errorf("entity update failed for %s (status %d)", profile.AgentID, status)
 	}
 	
-	return e.storeData(DomainAgents, profile.AgentID, "profile", body)
+	if err := e.storeData(DomainAgents, profile.AgentID, "profile", body); err != nil {
+		return err
+	}
+	if err := e.indexAgentRole(profile); err != nil {
+		log.Printf("Index role for agent %s: %v", profile.AgentID, err)
+	}
+	return nil
+}
+
+// adjustAgentLoad moves an agent's recorded load by delta, never below zero,
+// so an assignment or release shows before the agent's next heartbeat
+// reports its own count.
+func (e *Engine) adjustAgentLoad(agentID string, delta int) error {
+	agent, err := e.GetAgent(agentID)
+	if err != nil {
+		return err
+	}
+	load := agent.CurrentLoad + delta
+	if load < 0 {
+		load = 0
+	}
+	if load == agent.CurrentLoad {
+		return nil
+	}
+	agent.CurrentLoad = load
+	return e.saveAgent(*agent)
 }
 
 // RemoveAgent deregisters an agent.
@@ -174,7 +264,15 @@ func (e *Engine) FindAgentsByCapability(required []string) ([]t.AgentProfile, er
 	if err := json.Unmarshal(rsp.Response, &agents); err != nil {
 		return nil, err
 	}
-	return agents, nil
+	
+	// Drop agents that have gone quiet since their status was last written
+	live := agents[:0]
+	for _, agent := range agents {
+		if agent.Status != t.StatusOffline && !e.missedHeartbeats(agent.AgentID) {
+			live = append(live, agent)
+		}
+	}
+	return live, nil
 }
 
 // ═══════════════════════════════════════════════════════════════════════════════
@@ -183,9 +281,15 @@ func (e *Engine) FindAgentsByCapability(required []string) ([]t.AgentProfile, er
 // ═══════════════════════════════════════════════════════════════════════════════
 
 // CreateTask stores a new task specification.
-func (e *Engine) CreateTask(task t.TaskSpec) error {
+func (e *Engine) CreateTask(task t.TaskSpec) (err error) {
+	ctx, done := e.traceTaskFrom(task.TraceContext, task.TaskID, "engine.CreateTask")
+	defer done(&err)
+	
 	task.CreatedAt = time.Now()
 	task.Status = t.TaskPending
+	if len(task.TraceContext) == 0 {
+		task.TraceContext = injectTrace(ctx) // A new task starts its own trace
+	}
 	
 	body, err := json.Marshal(task)
 	if err != nil {
@@ -202,32 +306,54 @@ func (e *Engine) CreateTask(task t.TaskSpec) error {
 	// Register RDID for task access
 	nc.RelationRegister(e.Server, task.TaskID, e.Token, "write")
 	
-	// Store task data
-	return e.storeData(DomainTasks, task.TaskID, "spec", body)
+	// Log, then store task data
+	if err := e.recordDomainEvent(t.DomainTaskCreated, task.TaskID, task); err != nil {
+		return err
+	}
+	if err := e.storeData(DomainTasks, task.TaskID, "spec", body); err != nil {
+		return err
+	}
+	// The spec is stored; a stale catalog row is repaired by the next write
+	if err := e.indexTask(task); err != nil {
+		log.Printf("Index task %s: %v", task.TaskID, err)
+	}
+	if isTerminal(task.Status) {
+		e.forgetLive(task.TaskID)
+	}
+	return nil
 }
 
 // DecomposeTask breaks a parent task into sub-tasks.
 // Implements "contract-first decomposition" — sub-tasks must have verifiable outputs.
-// Returns the updated parent with sub-task IDs populated.
-func (e *Engine) DecomposeTask(parentID string, subTasks []t.TaskSpec) (*t.TaskSpec, error) {
+// Returns the updated parent with sub-task IDs populated, or a *DecompositionError
+// listing every consistency violation.
+func (e *Engine) DecomposeTask(parentID string, subTasks []t.TaskSpec) (_ *t.TaskSpec, err error) {
 	parent, err := e.GetTask(parentID)
 	if err != nil {
 		return nil, fmt.Errorf("get parent task: %w", err)
 	}
+	ctx, done := e.traceTaskFrom(parent.TraceContext, parentID, "engine.DecomposeTask",
+		attribute.Int("delegation.sub_tasks", len(subTasks)))
+	defer done(&err)
+	
+	inheritDeadline(parent, subTasks)
+	
+	// Budget, deadline, capability, criticality, permission, verifiability and
+	// dependency checks are all reported together
+	if report := checkDecomposition(parent, subTasks); !report.OK() {
+		return nil, &DecompositionError{Report: report}
+	}
 	
 	subIDs := make([]string, 0, len(subTasks))
 	for i := range subTasks {
 		sub := &subTasks[i]
 		sub.ParentTaskID = parentID
 		sub.DelegatorID = parent.DelegatorID
-		
-		// Contract-first: verify that the sub-task has adequate verifiability
-		if sub.Verifiability < 0.3 && !sub.IsLeaf {
-			return nil, fmt.Errorf(
-				"sub-task %s has low verifiability (%.2f); decompose further or add verification artifacts",
-				sub.TaskID, sub.Verifiability,
-			)
+		sub.DelegationDepth = parent.DelegationDepth
+		if sub.Priority == 0 {
+			sub.Priority = parent.Priority
 		}
+		sub.TraceContext = injectTrace(ctx)
 		
 		if err := e.CreateTask(*sub); err != nil {
 			return nil, fmt.Errorf("create sub-task %s: %w", sub.TaskID, err)
@@ -239,6 +365,13 @@ func (e *Engine) DecomposeTask(parentID string, subTasks []t.TaskSpec) (*t.TaskS
 	parent.Status = t.TaskDecomposed
 	parent.IsLeaf = false
 	
+	err = e.recordDomainEvent(t.DomainTaskDecomposed, parentID, t.TaskDecomposition{
+		ParentTaskID: parentID,
+		SubTaskIDs:   subIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
 	if err := e.UpdateTask(*parent); err != nil {
 		return nil, fmt.Errorf("update parent task: %w", err)
 	}
@@ -262,11 +395,22 @@ func (e *Engine) GetTask(taskID string) (*t.TaskSpec, error) {
 
 // UpdateTask persists task state changes.
 func (e *Engine) UpdateTask(task t.TaskSpec) error {
+	if err := e.recordDomainEvent(t.DomainTaskUpdated, task.TaskID, task); err != nil {
+		return err
+	}
+	return e.writeTask(task)
+}
+
+// writeTask stores and indexes a task spec without logging the change.
+func (e *Engine) writeTask(task t.TaskSpec) error {
 	body, err := json.Marshal(task)
 	if err != nil {
 		return err
 	}
-	return e.storeData(DomainTasks, task.TaskID, "spec", body)
+	if err := e.storeData(DomainTasks, task.TaskID, "spec", body); err != nil {
+		return err
+	}
+	return e.indexTask(task)
 }
 
 // ═══════════════════════════════════════════════════════════════════════════════
@@ -276,7 +420,15 @@ func (e *Engine) UpdateTask(task t.TaskSpec) error {
 
 // PublishTaskForBidding opens a task to the market via a secure channel.
 // Delegatee agents subscribe to the bidding channel and submit bids.
-func (e *Engine) PublishTaskForBidding(task t.TaskSpec) (string, error) {
+func (e *Engine) PublishTaskForBidding(task t.TaskSpec) (_ string, err error) {
+	_, done := e.traceTaskFrom(task.TraceContext, task.TaskID, "engine.PublishTaskForBidding")
+	defer done(&err)
+	
+	// Only tasks whose predecessors are verified may enter the market
+	if waiting := e.unmetDependencies(task); len(waiting) > 0 {
+		return "", fmt.Errorf("task %s waiting on dependencies %v", task.TaskID, waiting)
+	}
+	
 	// Create a secure channel for this task's bidding process
 	channelName := fmt.Sprintf("bid_%s", task.TaskID)
 	rdid, err := nc.InitChannel(e.Server, channelName, e.Token, true)
@@ -289,11 +441,15 @@ func (e *Engine) PublishTaskForBidding(task t.TaskSpec) (string, error) {
 		return "", err
 	}
 	
-	// Publish task spec to the bidding channel
+	// Publish task spec to the bidding channel; bidders join its trace
 	taskBytes, _ := json.Marshal(task)
+	span := e.clientSpan(task.TaskID, "natsclient.SecureChannelPublish", attribute.String("nats.channel", channelName))
+	start := time.Now()
 	err = nc.SecureChannelPublish(
 		taskBytes, e.Server, channelName, e.Token, rdid, 3600, // 1hr expiry
 	)
+	e.Metrics.observeNATS("publish", start, 0, errors.Is(err, nats.ErrTimeout))
+	endClientSpan(span, err)
 	if err != nil {
 		return "", fmt.Errorf("publish to bidding channel: %w", err)
 	}
@@ -303,62 +459,205 @@ func (e *Engine) PublishTaskForBidding(task t.TaskSpec) (string, error) {
 }
 
 // SubmitBid allows a delegatee agent to bid on a task.
-func (e *Engine) SubmitBid(bid t.Bid) error {
+func (e *Engine) SubmitBid(bid t.Bid) (err error) {
+	_, done := e.traceTask(bid.TaskID, "engine.SubmitBid", attribute.String("delegation.bid_id", bid.BidID))
+	defer done(&err)
+	
 	bid.SubmittedAt = time.Now()
 	body, err := json.Marshal(bid)
 	if err != nil {
 		return err
 	}
 	
+	if err := e.recordDomainEvent(t.DomainBidSubmitted, bid.TaskID, bid); err != nil {
+		return err
+	}
+	
 	// Store bid under the Bids domain keyed by task
-	return e.storeData(DomainBids, bid.TaskID, bid.BidID, body)
+	if err := e.storeData(DomainBids, bid.TaskID, bid.BidID, body); err != nil {
+		return err
+	}
+	
+	// Keep the per-task bid list so the book can be re-ranked later
+	return e.appendList(DomainBids, bid.TaskID, "bids", bid)
+}
+
+// GetBids retrieves all bids submitted for a task.
+func (e *Engine) GetBids(taskID string) ([]t.Bid, error) {
+	data, err := e.retrieveData(DomainBids, taskID, "bids")
+	if err != nil {
+		return nil, err
+	}
+	var bids []t.Bid
+	if err := json.Unmarshal(data, &bids); err != nil {
+		return nil, fmt.Errorf("unmarshal bids: %w", err)
+	}
+	return bids, nil
 }
 
 // AcceptBid selects a bid and creates a delegation contract.
-func (e *Engine) AcceptBid(bid t.Bid, terms t.ContractTerms) (*t.DelegationContract, error) {
-	now := time.Now()
-	contract := &t.DelegationContract{
-		ContractID:  fmt.Sprintf("contract_%s_%s", bid.TaskID, bid.AgentID),
-		TaskID:      bid.TaskID,
-		DelegatorID: e.SelfID,
-		DelegateeID: bid.AgentID,
-		AcceptedBid: &bid,
-		Terms:       terms,
-		Status:      t.ContractActive,
-		CreatedAt:   now,
-		SignedAt:    &now,
+// The runner-up in the ranked bid book is recorded as the contract's backup agent.
+func (e *Engine) AcceptBid(bid t.Bid, terms t.ContractTerms) (_ *t.DelegationContract, err error) {
+	task, err := e.GetTask(bid.TaskID)
+	if err != nil {
+		return nil, err
 	}
+	_, done := e.traceTaskFrom(task.TraceContext, task.TaskID, "engine.AcceptBid",
+		attribute.String("delegation.delegatee_id", bid.AgentID))
+	defer done(&err)
 	
-	body, err := json.Marshal(contract)
+	// Record who answers to whom, refusing loops back up the chain. Checked
+	// first so an unacceptable bid never costs another task its slot.
+	chain, err := e.extendChain(task, bid.AgentID, contractID(bid.TaskID, bid.AgentID))
 	if err != nil {
 		return nil, err
 	}
 	
-	// Store contract
-	if err := e.storeData(DomainContracts, contract.ContractID, "terms", body); err != nil {
-		return nil, err
+	// A saturated winner may make room by preempting lower-priority work
+	if e.Preemption.Enabled {
+		if victim, err := e.PreemptFor(task.TaskID, bid.AgentID); err != nil {
+			log.Printf("Preempt on %s for task %s: %v", bid.AgentID, task.TaskID, err)
+		} else if victim != "" {
+			log.Printf("Task %s preempted on %s to make room for %s", victim, bid.AgentID, task.TaskID)
+		}
 	}
 	
-	// Update task with assigned delegatee
-	task, err := e.GetTask(bid.TaskID)
-	if err != nil {
+	now := time.Now()
+	contract := &t.DelegationContract{
+		ContractID:    contractID(bid.TaskID, bid.AgentID),
+		TaskID:        bid.TaskID,
+		DelegatorID:   e.SelfID,
+		DelegateeID:   bid.AgentID,
+		AcceptedBid:   &bid,
+		Terms:         terms,
+		Status:        t.ContractActive,
+		Permissions:   task.Permissions,
+		BackupAgentID: e.selectBackupAgent(task, bid),
+		Version:       1,
+		CreatedAt:     now,
+		SignedAt:      &now,
+	}
+	
+	// Log, then store contract
+	if err := e.recordDomainEvent(t.DomainBidAccepted, contract.TaskID, contract); err != nil {
+		return nil, err
+	}
+	if err := e.writeContract(contract); err != nil {
 		return nil, err
 	}
+	
+	if e.Metrics != nil {
+		bids, _ := e.GetBids(task.TaskID)
+		e.Metrics.observeAssignment(task, len(bids), now)
+	}
+	
+	// Update task with assigned delegatee
 	task.DelegateeID = bid.AgentID
+	task.ContractID = contract.ContractID
+	task.DelegationChain = chain
 	task.Status = t.TaskAssigned
 	task.StartedAt = &now
 	if err := e.UpdateTask(*task); err != nil {
 		return nil, err
 	}
+	if err := e.adjustAgentLoad(bid.AgentID, 1); err != nil {
+		log.Printf("Record load of %s: %v", bid.AgentID, err)
+	}
+	
+	// Hand predecessor results to the delegatee as the task starts
+	if len(task.DependsOn) > 0 {
+		if err := e.stageDependencyInputs(task); err != nil {
+			log.Printf("Stage inputs for task %s: %v", task.TaskID, err)
+		}
+	}
+	
+	// A re-allocated task continues from its last verified checkpoint
+	if err := e.handOffCheckpoint(task); err != nil {
+		log.Printf("Resume checkpoint for task %s: %v", task.TaskID, err)
+	}
 	
 	// Grant permissions to delegatee via RDID
 	nc.RelationRegister(e.Server, bid.TaskID, e.Token, "write")
 	
-	log.Printf("Contract %s created: %s → %s for task %s",
-		contract.ContractID, e.SelfID, bid.AgentID, bid.TaskID)
+	// Open the backup's agent channel now so a failover offer needs no setup
+	if contract.BackupAgentID != "" {
+		if _, err := e.SetupAgentChannel(task.TaskID, contract.BackupAgentID); err != nil {
+			log.Printf("Backup channel for %s on task %s: %v", contract.BackupAgentID, task.TaskID, err)
+		}
+	}
+	
+	log.Printf("Contract %s created: %s → %s for task %s (backup: %q)",
+		contract.ContractID, e.SelfID, bid.AgentID, bid.TaskID, contract.BackupAgentID)
 	return contract, nil
 }
 
+// GetContract retrieves a delegation contract by ID.
+func (e *Engine) GetContract(contractID string) (*t.DelegationContract, error) {
+	data, err := e.retrieveData(DomainContracts, contractID, "terms")
+	if err != nil {
+		return nil, err
+	}
+	var contract t.DelegationContract
+	if err := json.Unmarshal(data, &contract); err != nil {
+		return nil, fmt.Errorf("unmarshal contract: %w", err)
+	}
+	return &contract, nil
+}
+
+// GetContractVersion retrieves a specific historical version of a contract.
+func (e *Engine) GetContractVersion(contractID string, version int) (*t.DelegationContract, error) {
+	data, err := e.retrieveData(DomainContracts, contractID, fmt.Sprintf("terms_v%d", version))
+	if err != nil {
+		return nil, err
+	}
+	var contract t.DelegationContract
+	if err := json.Unmarshal(data, &contract); err != nil {
+		return nil, fmt.Errorf("unmarshal contract: %w", err)
+	}
+	return &contract, nil
+}
+
+// storeContract persists a contract under the Contracts domain, both as the
+// current document and as an immutable snapshot of its version.
+func (e *Engine) storeContract(contract *t.DelegationContract) error {
+	if err := e.recordDomainEvent(t.DomainContractUpdated, contract.TaskID, contract); err != nil {
+		return err
+	}
+	return e.writeContract(contract)
+}
+
+// writeContract stores and indexes a contract without logging the change. The
+// terms_v{n} snapshot is written once, when a version first appears; later
+// status changes (breach, termination, settlement) only touch the current
+// document.
+func (e *Engine) writeContract(contract *t.DelegationContract) error {
+	body, err := json.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	if contract.Version > 0 {
+		aspect := fmt.Sprintf("terms_v%d", contract.Version)
+		_, err := e.retrieveData(DomainContracts, contract.ContractID, aspect)
+		switch {
+		case isNotFound(err):
+			if err := e.storeData(DomainContracts, contract.ContractID, aspect, body); err != nil {
+				return err
+			}
+		case err != nil:
+			return fmt.Errorf("check %s of %s: %w", aspect, contract.ContractID, err)
+		}
+	}
+	if err := e.storeData(DomainContracts, contract.ContractID, "terms", body); err != nil {
+		return err
+	}
+	return e.indexContract(*contract)
+}
+
+// contractID derives the contract identifier for a task/delegatee pair.
+func contractID(taskID, agentID string) string {
+	return fmt.Sprintf("contract_%s_%s", taskID, agentID)
+}
+
 // ═══════════════════════════════════════════════════════════════════════════════
 // MONITORING (Section 4.5)
 // Uses secure channels for real-time event streaming.
@@ -375,28 +674,38 @@ func (e *Engine) SetupMonitoringChannel(taskID string) (channelName, rdid string
 	return channelName, rdid, nil
 }
 
-// EmitMonitorEvent publishes a monitoring event for a task.
-func (e *Engine) EmitMonitorEvent(event t.MonitorEvent) error {
+// EmitMonitorEvent publishes a monitoring event for a task, as far as the
+// task's contracted monitoring mode allows.
+func (e *Engine) EmitMonitorEvent(event t.MonitorEvent) (err error) {
+	_, done := e.traceTask(event.TaskID, "engine.EmitMonitorEvent", attribute.String("delegation.event_type", string(event.EventType)))
+	defer done(&err)
+	
 	event.Timestamp = time.Now()
-	body, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
+	e.observeEvent(event)
 	
-	// Persist event to audit log
-	eventKey := fmt.Sprintf("%s_%s", event.EventID, event.Timestamp.Format(time.RFC3339Nano))
-	if err := e.storeData(DomainMonitoring, event.TaskID, eventKey, body); err != nil {
-		return err
+	// The contracted monitoring mode decides what leaves this engine and when
+	mode, interval := e.monitoringTerms(event.TaskID)
+	switch {
+	case !mode.Discloses(event.EventType):
+		return nil // Kept private; only the local aggregator sees it
+	case mode == t.MonitorPeriodic && interval > 0:
+		return e.discloseEvents(e.queueReport(event, interval))
+	default:
+		return e.discloseEvent(event)
 	}
-	
-	// Publish to monitoring channel
-	channelName := fmt.Sprintf("monitor_%s", event.TaskID)
-	rdid, _ := nc.RelationRetrieve(e.Server, channelName, e.Token)
-	if rdid != "" {
-		nc.SecureChannelPublish(body, e.Server, channelName, e.Token, rdid, 86400)
+}
+
+// GetLatestMonitorEvent retrieves the most recent monitoring event for a task.
+func (e *Engine) GetLatestMonitorEvent(taskID string) (*t.MonitorEvent, error) {
+	data, err := e.retrieveData(DomainMonitoring, taskID, "latest")
+	if err != nil {
+		return nil, err
 	}
-	
-	return nil
+	var event t.MonitorEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("unmarshal monitor event: %w", err)
+	}
+	return &event, nil
 }
 
 // SubscribeToMonitoring listens for monitoring events on a task.
@@ -409,11 +718,13 @@ func (e *Engine) SubscribeToMonitoring(taskID string, handler func(t.MonitorEven
 	
 	_, err := nc.SecureChannelQueueSubscribe(
 		e.Server, channelName, "monitors", e.Token, rdid,
-		func(msg interface{}) {
-			// Note: actual type is *nats.Msg but simplified for framework illustration
-			// In production, unmarshal msg.Data into MonitorEvent
+		func(msg *nats.Msg) {
 			var event t.MonitorEvent
-			// json.Unmarshal(msg.Data, &event)
+			if err := json.Unmarshal(msg.Data, &event); err != nil {
+				log.Printf("Malformed monitoring event on %s: %v", channelName, err)
+				return
+			}
+			e.receiveEvent(event)
 			handler(event)
 		},
 	)
@@ -426,14 +737,27 @@ func (e *Engine) SubscribeToMonitoring(taskID string, handler func(t.MonitorEven
 // ═══════════════════════════════════════════════════════════════════════════════
 
 // RaiseTrigger records an adaptive coordination trigger and initiates response.
-func (e *Engine) RaiseTrigger(trigger t.AdaptiveTrigger) error {
+func (e *Engine) RaiseTrigger(trigger t.AdaptiveTrigger) (err error) {
+	_, done := e.traceTask(trigger.TaskID, "engine.RaiseTrigger", attribute.String("delegation.trigger_type", string(trigger.Type)))
+	defer done(&err)
+	
 	trigger.Timestamp = time.Now()
+	if trigger.TriggerID == "" {
+		trigger.TriggerID = fmt.Sprintf("%s_%s_%d", trigger.Type, trigger.TaskID, trigger.Timestamp.UnixNano())
+	}
+	e.Metrics.observeTrigger(trigger.Type)
 	body, _ := json.Marshal(trigger)
 	
 	// Persist trigger
+	if err := e.recordDomainEvent(t.DomainTriggerRaised, trigger.TaskID, trigger); err != nil {
+		return err
+	}
 	if err := e.storeData(DomainTriggers, trigger.TaskID, trigger.TriggerID, body); err != nil {
 		return err
 	}
+	if err := e.appendAudit(trigger.TaskID, AuditTrigger, trigger.TriggerID, body); err != nil {
+		return fmt.Errorf("audit trigger %s: %w", trigger.TriggerID, err)
+	}
 	
 	log.Printf("TRIGGER [%s] on task %s: %s (urgent=%v)",
 		trigger.Type, trigger.TaskID, trigger.Description, trigger.Urgent)
@@ -444,49 +768,78 @@ func (e *Engine) RaiseTrigger(trigger t.AdaptiveTrigger) error {
 		return err
 	}
 	
+	severity := t.CriticalityMedium
+	if trigger.Urgent {
+		severity = t.CriticalityHigh
+	}
+	e.notify(t.Notification{
+		Kind:            t.NotifyTrigger,
+		Severity:        severity,
+		TaskID:          task.TaskID,
+		TaskCriticality: task.Criticality,
+		AgentID:         trigger.AgentID,
+		Title:           fmt.Sprintf("%s trigger on task %s", trigger.Type, task.TaskID),
+		Body:            trigger.Description,
+	})
+	
 	return e.evaluateAndRespond(task, trigger)
 }
 
-// evaluateAndRespond implements the adaptive response cycle from Figure 2.
+// evaluateAndRespond implements the adaptive response cycle from Figure 2:
+// the active ResponsePolicy picks the response, which is recorded and carried out.
 func (e *Engine) evaluateAndRespond(task *t.TaskSpec, trigger t.AdaptiveTrigger) error {
-	// Step A: Check reversibility
-	if !task.Reversible && trigger.Urgent {
-		// Irreversible + urgent → immediate termination or human escalation
-		log.Printf("ESCALATION: Irreversible task %s with urgent trigger — halting", task.TaskID)
-		task.Status = t.TaskCancelled
-		return e.UpdateTask(*task)
+	decision := e.decide(e.activePolicy(), task, trigger)
+	if err := e.storeDecision(decision); err != nil {
+		log.Printf("Store decision for trigger %s: %v", trigger.TriggerID, err)
 	}
-	
-	// Step B: Check urgency
-	if trigger.Urgent {
-		// Fast-path: re-delegate immediately
-		return e.reDelegate(task)
+	log.Printf("Policy %s v%d → %s on task %s (rule %q)",
+		decision.PolicyName, decision.PolicyVersion, decision.Action, task.TaskID, decision.Rule)
+	return e.respond(task, trigger, decision)
+}
+
+// proposeBudgetExtension offers the delegatee a budget amendment of extendBy
+// (a fraction of MaxCost), or escalates once the contract has used up its
+// extension allowance.
+func (e *Engine) proposeBudgetExtension(task *t.TaskSpec, trigger t.AdaptiveTrigger, extendBy float64) error {
+	if task.ContractID == "" {
+		return e.escalate(task, "budget overrun on a task with no active contract")
 	}
-	
-	// Step C: Determine scope — can we just adjust parameters?
-	switch trigger.Type {
-	case t.TriggerIntBudgetOverrun:
-		// Try to extend budget before re-delegating
-		log.Printf("Budget overrun on task %s — evaluating extension", task.TaskID)
-		task.MaxBudget *= 1.2 // 20% extension
-		return e.UpdateTask(*task)
-	
-	case t.TriggerIntPerfDrop, t.TriggerIntUnresponsive:
-		// Re-delegate the task
-		return e.reDelegate(task)
-	
-	case t.TriggerIntVerifyFail:
-		// Request re-execution
-		task.Status = t.TaskReAllocating
-		return e.UpdateTask(*task)
-	
-	default:
-		log.Printf("Non-urgent trigger %s on task %s — monitoring", trigger.Type, task.TaskID)
-		return nil
+	contract, err := e.GetContract(task.ContractID)
+	if err != nil {
+		return err
 	}
+	if extensionsExhausted(contract) {
+		return e.escalate(task, fmt.Sprintf("budget overrun after %d extensions", contract.Extensions))
+	}
+	
+	newCost := contract.Terms.MaxCost * (1 + extendBy)
+	_, err = e.ProposeAmendment(t.ContractAmendment{
+		ContractID: contract.ContractID,
+		NewMaxCost: &newCost,
+		Reason:     fmt.Sprintf("budget overrun: %s", trigger.Description),
+	})
+	return err
+}
+
+// escalate hands a task to human oversight: it is marked disputed and no
+// further automatic response is attempted.
+func (e *Engine) escalate(task *t.TaskSpec, reason string) error {
+	log.Printf("ESCALATION: task %s — %s", task.TaskID, reason)
+	e.notify(t.Notification{
+		Kind:            t.NotifyEscalation,
+		Severity:        escalationSeverity(task),
+		TaskID:          task.TaskID,
+		TaskCriticality: task.Criticality,
+		AgentID:         task.DelegateeID,
+		Title:           fmt.Sprintf("Task %s needs human review", task.TaskID),
+		Body:            reason,
+	})
+	task.Status = t.TaskDisputed
+	return e.UpdateTask(*task)
 }
 
-// reDelegate cancels current assignment and re-publishes for bidding.
+// reDelegate cancels current assignment and hands the task to the contract's
+// backup agent if it accepts; otherwise the task is re-published for bidding.
 func (e *Engine) reDelegate(task *t.TaskSpec) error {
 	log.Printf("RE-DELEGATING task %s (was assigned to %s)", task.TaskID, task.DelegateeID)
 	
@@ -502,14 +855,25 @@ func (e *Engine) reDelegate(task *t.TaskSpec) error {
 		})
 	}
 	
+	// Fast path: offer the task to the backup agent before opening the market
+	handed, err := e.failoverToBackup(task)
+	if err != nil {
+		log.Printf("Failover for task %s: %v — falling back to re-bidding", task.TaskID, err)
+	}
+	if handed {
+		return nil
+	}
+	
 	task.DelegateeID = ""
+	task.ContractID = ""
+	task.DelegationChain = nil
 	task.Status = t.TaskReAllocating
 	if err := e.UpdateTask(*task); err != nil {
 		return err
 	}
 	
 	// Re-publish for bidding
-	_, err := e.PublishTaskForBidding(*task)
+	_, err = e.PublishTaskForBidding(*task)
 	return err
 }
 
@@ -523,6 +887,9 @@ func (e *Engine) RecordReputation(record t.ReputationRecord) error {
 	record.RecordedAt = time.Now()
 	body, _ := json.Marshal(record)
 	
+	if err := e.recordDomainEvent(t.DomainReputationRecord, record.AgentID, record); err != nil {
+		return err
+	}
 	key := fmt.Sprintf("%s_%s", record.TaskID, record.RecordedAt.Format(time.RFC3339Nano))
 	return e.storeData(DomainReputation, record.AgentID, key, body)
 }
@@ -547,9 +914,12 @@ func (e *Engine) ComputeTrustScore(agentID string) (float64, error) {
 	if err != nil || len(records) == 0 {
 		return 0.5, err // Default neutral
 	}
-	
+	return trustScore(records, time.Now()), nil
+}
+
+// trustScore weighs each record's mean score by its age at now.
+func trustScore(records []t.ReputationRecord, now time.Time) float64 {
 	var weightedSum, totalWeight float64
-	now := time.Now()
 	for _, rec := range records {
 		age := now.Sub(rec.RecordedAt).Hours() / 24.0 // Days old
 		weight := 1.0 / (1.0 + age/30.0)              // 30-day half-life
@@ -560,9 +930,9 @@ func (e *Engine) ComputeTrustScore(agentID string) (float64, error) {
 	}
 	
 	if totalWeight == 0 {
-		return 0.5, nil
+		return 0.5 // Default neutral
 	}
-	return weightedSum / totalWeight, nil
+	return weightedSum / totalWeight
 }
 
 // ═══════════════════════════════════════════════════════════════════════════════
@@ -586,8 +956,15 @@ func (e *Engine) SubmitForVerification(taskID string, artifact []byte) error {
 }
 
 // RecordVerification records verification outcome and updates task + reputation.
-func (e *Engine) RecordVerification(result t.VerificationResult) error {
+func (e *Engine) RecordVerification(result t.VerificationResult) (err error) {
+	_, done := e.traceTask(result.TaskID, "engine.RecordVerification", attribute.Bool("delegation.passed", result.Passed))
+	defer done(&err)
+	
+	e.Metrics.observeVerification(result.Passed)
 	body, _ := json.Marshal(result)
+	if err := e.recordDomainEvent(t.DomainTaskVerified, result.TaskID, result); err != nil {
+		return err
+	}
 	if err := e.storeData(DomainTasks, result.TaskID, "verification", body); err != nil {
 		return err
 	}
@@ -596,6 +973,7 @@ func (e *Engine) RecordVerification(result t.VerificationResult) error {
 	if err != nil {
 		return err
 	}
+	prevStatus := task.Status
 	
 	if result.Passed {
 		now := time.Now()
@@ -615,18 +993,52 @@ func (e *Engine) RecordVerification(result t.VerificationResult) error {
 		})
 	} else {
 		task.Status = t.TaskFailed
-		// Trigger re-delegation
-		e.RaiseTrigger(t.AdaptiveTrigger{
+	}
+	
+	if err := e.UpdateTask(*task); err != nil {
+		return err
+	}
+	// The task no longer occupies its delegatee
+	if !isTerminal(prevStatus) && task.DelegateeID != "" {
+		if err := e.adjustAgentLoad(task.DelegateeID, -1); err != nil {
+			log.Printf("Release load of %s: %v", task.DelegateeID, err)
+		}
+	}
+	
+	// Trigger re-delegation only once the failure is stored, so the response's
+	// re-allocation is what the task (and its parent's roll-up) ends up showing
+	if !result.Passed {
+		if err := e.RaiseTrigger(t.AdaptiveTrigger{
 			TriggerID:   fmt.Sprintf("verfail_%s", result.TaskID),
 			TaskID:      result.TaskID,
 			Type:        t.TriggerIntVerifyFail,
 			AgentID:     task.DelegateeID,
 			Description: result.Details,
 			Urgent:      task.Criticality == t.CriticalityCritical,
-		})
+		}); err != nil {
+			log.Printf("Respond to failed verification of %s: %v", result.TaskID, err)
+		}
 	}
 	
-	return e.UpdateTask(*task)
+	// Work is accepted — compute what is owed under the contract
+	if result.Passed && task.ContractID != "" {
+		if _, err := e.SettleContract(task.ContractID); err != nil {
+			log.Printf("Settle contract %s: %v", task.ContractID, err)
+		}
+	}
+	
+	if task.ParentTaskID != "" {
+		// A verified sub-task may unblock its dependent siblings
+		if result.Passed {
+			if _, err := e.PublishReadySubTasks(task.ParentTaskID); err != nil {
+				log.Printf("Publish ready sub-tasks of %s: %v", task.ParentTaskID, err)
+			}
+		}
+		if _, err := e.RollupParent(task.ParentTaskID); err != nil {
+			log.Printf("Roll up parent %s: %v", task.ParentTaskID, err)
+		}
+	}
+	return nil
 }
 
 // ═══════════════════════════════════════════════════════════════════════════════
@@ -663,7 +1075,7 @@ func (e *Engine) RevokePermission(delegateeID, resource string) error {
 
 // SetupAgentChannel creates a secure channel between two agents for a task.
 func (e *Engine) SetupAgentChannel(taskID, delegateeID string) (string, error) {
-	channelName := fmt.Sprintf("task_%s_%s_%s", taskID, e.SelfID, delegateeID)
+	channelName := agentChannelName(taskID, e.SelfID, delegateeID)
 	rdid, err := nc.InitChannel(e.Server, channelName, e.Token, true)
 	if err != nil {
 		return "", err
@@ -672,6 +1084,103 @@ func (e *Engine) SetupAgentChannel(taskID, delegateeID string) (string, error) {
 	return channelName, nil
 }
 
+// SubscribeAgentMessages listens on the agent channel shared with delegatorID for
+// a task. Used by delegatees to receive proposals and notices from their delegator.
+func (e *Engine) SubscribeAgentMessages(taskID, delegatorID string, handler func(t.AgentMessage)) error {
+	channelName := agentChannelName(taskID, delegatorID, e.SelfID)
+	return e.subscribeAgentChannel(channelName, "agent", func(msg t.AgentMessage, _ *nats.Msg) {
+		handler(msg)
+	})
+}
+
+// agentChannelName names the channel shared by a delegator and delegatee for a task.
+func agentChannelName(taskID, delegatorID, delegateeID string) string {
+	return fmt.Sprintf("task_%s_%s_%s", taskID, delegatorID, delegateeID)
+}
+
+// newAgentMessage wraps a payload in the agent channel envelope.
+func (e *Engine) newAgentMessage(taskID string, kind t.AgentMessageType, payload interface{}) ([]byte, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(t.AgentMessage{
+		Type:         kind,
+		TaskID:       taskID,
+		From:         e.SelfID,
+		Payload:      raw,
+		SentAt:       time.Now(),
+		TraceContext: injectTrace(e.activeTrace(taskID)),
+	})
+}
+
+// publishAgentMessage sends a one-way message on an agent channel.
+func (e *Engine) publishAgentMessage(channelName, taskID string, kind t.AgentMessageType, payload interface{}) error {
+	rdid, _ := nc.RelationRetrieve(e.Server, channelName, e.Token)
+	if rdid == "" {
+		return fmt.Errorf("no RDID for agent channel %s", channelName)
+	}
+	body, err := e.newAgentMessage(taskID, kind, payload)
+	if err != nil {
+		return err
+	}
+	span := e.clientSpan(taskID, "natsclient.SecureChannelPublish",
+		attribute.String("nats.channel", channelName), attribute.String("delegation.message_type", string(kind)))
+	start := time.Now()
+	err = nc.SecureChannelPublish(body, e.Server, channelName, e.Token, rdid, 86400)
+	e.Metrics.observeNATS("publish", start, 0, errors.Is(err, nats.ErrTimeout))
+	endClientSpan(span, err)
+	return err
+}
+
+// requestAgentMessage sends a message on an agent channel and waits for the reply.
+func (e *Engine) requestAgentMessage(channelName, taskID string, kind t.AgentMessageType, payload interface{}, timeout time.Duration) ([]byte, error) {
+	rdid, _ := nc.RelationRetrieve(e.Server, channelName, e.Token)
+	if rdid == "" {
+		return nil, fmt.Errorf("no RDID for agent channel %s", channelName)
+	}
+	body, err := e.newAgentMessage(taskID, kind, payload)
+	if err != nil {
+		return nil, err
+	}
+	span := e.clientSpan(taskID, "natsclient.SecureChannelRequest",
+		attribute.String("nats.channel", channelName), attribute.String("delegation.message_type", string(kind)))
+	start := time.Now()
+	msg, err := nc.SecureChannelRequest(e.Server, channelName, rdid, e.Token, body, timeout)
+	e.Metrics.observeNATS("request", start, 0, errors.Is(err, nats.ErrTimeout))
+	endClientSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return msg.Data, nil
+}
+
+// subscribeAgentChannel decodes agent channel envelopes and hands them to handler
+// along with the raw message so request/reply handlers can respond.
+func (e *Engine) subscribeAgentChannel(channelName, queue string, handler func(t.AgentMessage, *nats.Msg)) error {
+	rdid, _ := nc.RelationRetrieve(e.Server, channelName, e.Token)
+	if rdid == "" {
+		return fmt.Errorf("no agent channel %s", channelName)
+	}
+	
+	_, err := nc.SecureChannelQueueSubscribe(
+		e.Server, channelName, queue, e.Token, rdid,
+		func(msg *nats.Msg) {
+			var env t.AgentMessage
+			if err := json.Unmarshal(msg.Data, &env); err != nil {
+				log.Printf("Malformed message on %s: %v", channelName, err)
+				return
+			}
+			// Work done for the message joins the sender's trace
+			_, done := e.traceTaskFrom(env.TraceContext, env.TaskID, "engine.HandleMessage",
+				attribute.String("delegation.message_type", string(env.Type)), attribute.String("delegation.from", env.From))
+			defer done(nil)
+			handler(env, msg)
+		},
+	)
+	return err
+}
+
 // ═══════════════════════════════════════════════════════════════════════════════
 // INTERNAL HELPERS — natsclient data store/retrieve wrappers
 // ═══════════════════════════════════════════════════════════════════════════════
@@ -694,17 +1203,194 @@ func (e *Engine) storeData(domain, entity, aspect string, data []byte) error {
 	nc.SetRDID(dflags, rdid)
 	nc.SetAspect(dflags, aspect)
 	
+	span := e.clientSpan(entity, "natsclient.Post", storageAttrs(domain, entity, aspect)...)
+	start := time.Now()
 	rsp := nc.Post(e.Server, data, dflags, e.Token)
+	e.Metrics.observeNATS("post", start, rsp.Header.Status, false)
+	span.SetAttributes(attribute.Int("nats.status", rsp.Header.Status))
 	if rsp.Header.Status != http.StatusOK {
-		return fmt.Errorf("store %s/%s/%s failed: %s (status %d)",
+		err := fmt.Errorf("store %s/%s/%s failed: %s (status %d)",
 			domain, entity, aspect, rsp.Header.ErrorStr, rsp.Header.Status)
+		endClientSpan(span, err)
+		return err
 	}
+	endClientSpan(span, nil)
 	return nil
 }
 
+// ErrNotFound is wrapped by reads of data the store does not hold, so callers
+// can tell a missing index from a read that failed.
+var ErrNotFound = errors.New("not found")
+
+// isNotFound reports whether err means the data does not exist yet.
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// retrieveJSON reads domain/entity/aspect into v. It returns false when the
+// store holds nothing there; any other failure is returned, so a transient
+// error is never mistaken for an empty index and written back over it.
+func (e *Engine) retrieveJSON(domain, entity, aspect string, v interface{}) (bool, error) {
+	data, err := e.retrieveData(domain, entity, aspect)
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(data) == 0 {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("unmarshal %s/%s/%s: %w", domain, entity, aspect, err)
+	}
+	return true, nil
+}
+
+// appendList appends item to a JSON array stored under domain/entity/aspect,
+// creating the list on first use. Used for indexes the store cannot enumerate.
+func (e *Engine) appendList(domain, entity, aspect string, item interface{}) error {
+	var list []json.RawMessage
+	if _, err := e.retrieveJSON(domain, entity, aspect, &list); err != nil {
+		return err
+	}
+	
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(append(list, raw))
+	if err != nil {
+		return err
+	}
+	return e.storeData(domain, entity, aspect, body)
+}
+
+// appendSharedList appends item to this engine's shard of a list that any
+// engine may add to.
+func (e *Engine) appendSharedList(domain, entity, aspect string, item interface{}) error {
+	if err := e.registerWriter(domain, entity, aspect); err != nil {
+		return err
+	}
+	e.listMu.Lock()
+	defer e.listMu.Unlock()
+	return e.appendList(domain, entity, e.shardAspect(aspect), item)
+}
+
+// readSharedList concatenates every engine's shard of a list written by
+// appendSharedList. A list nobody has written is empty; read failures are
+// returned.
+func (e *Engine) readSharedList(domain, entity, aspect string) ([]json.RawMessage, error) {
+	writers, err := e.shardWriters(domain, entity, aspect)
+	if err != nil {
+		return nil, err
+	}
+	var all []json.RawMessage
+	for _, w := range writers {
+		var shard []json.RawMessage
+		if _, err := e.retrieveJSON(domain, entity, aspect+"_"+w, &shard); err != nil {
+			return nil, fmt.Errorf("read %s shard %s of %s: %w", aspect, w, entity, err)
+		}
+		all = append(all, shard...)
+	}
+	return all, nil
+}
+
+// ─── Writer Shards ───────────────────────────────────────────────────────────
+// The store has no conditional writes, so an index that several engines update
+// is split into one shard per engine: each engine read-modify-writes only
+// {aspect}_{SelfID}, under its own mutex, and readers merge every shard listed
+// in {aspect}_writers. Only joining that writer list races across processes;
+// registration is re-read to confirm it and re-checked every WriterRecheck.
+
+// WriterRecheck is how long a confirmed shard registration is trusted before
+// the engine looks again that no other engine has overwritten it.
+const WriterRecheck = time.Minute
+
+// shardAspect is the aspect this engine writes its shard of aspect under.
+func (e *Engine) shardAspect(aspect string) string {
+	return aspect + "_" + e.SelfID
+}
+
+// registerWriter makes sure this engine is listed as a writer of aspect.
+func (e *Engine) registerWriter(domain, entity, aspect string) error {
+	key := domain + "/" + entity + "/" + aspect
+	e.writerMu.Lock()
+	defer e.writerMu.Unlock()
+	if seen, ok := e.writers[key]; ok && time.Since(seen) < WriterRecheck {
+		return nil
+	}
+	
+	for attempt := 0; attempt < 3; attempt++ {
+		writers, err := e.shardWriters(domain, entity, aspect)
+		if err != nil {
+			return err
+		}
+		listed := false
+		for _, w := range writers {
+			listed = listed || w == e.SelfID
+		}
+		if listed {
+			if e.writers == nil {
+				e.writers = make(map[string]time.Time)
+			}
+			e.writers[key] = time.Now()
+			return nil
+		}
+		
+		body, err := json.Marshal(append(writers, e.SelfID))
+		if err != nil {
+			return err
+		}
+		if err := e.storeData(domain, entity, aspect+"_writers", body); err != nil {
+			return err
+		}
+		// Give a concurrent registration time to land before re-reading
+		time.Sleep(time.Duration(50+rand.Intn(100)) * time.Millisecond)
+	}
+	return fmt.Errorf("could not register %s as a writer of %s", e.SelfID, key)
+}
+
+// shardWriters lists the engines that keep a shard of aspect.
+func (e *Engine) shardWriters(domain, entity, aspect string) ([]string, error) {
+	var writers []string
+	if _, err := e.retrieveJSON(domain, entity, aspect+"_writers", &writers); err != nil {
+		return nil, fmt.Errorf("writers of %s/%s/%s: %w", domain, entity, aspect, err)
+	}
+	return writers, nil
+}
+
+// runEvery calls fn every interval on a background goroutine until stop is called.
+func runEvery(interval time.Duration, fn func()) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				fn()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
 // retrieveData wraps natsclient.Get to read data from domain/entity/aspect.
 func (e *Engine) retrieveData(domain, entity, aspect string) ([]byte, error) {
+	return e.retrieveEncrypted(domain, entity, aspect, "")
+}
+
+// retrieveEncrypted reads like retrieveData, but when pubKey is set the backend
+// returns the data encrypted to that base64 ECC public key (encryptDataPKey).
+func (e *Engine) retrieveEncrypted(domain, entity, aspect, pubKey string) ([]byte, error) {
 	rdid, status := nc.RelationRetrieve(e.Server, entity, e.Token)
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("no RDID for %s/%s: %w", domain, entity, ErrNotFound)
+	}
 	if status != http.StatusOK {
 		return nil, fmt.Errorf("no RDID for %s/%s (status %d)", domain, entity, status)
 	}
@@ -716,11 +1402,26 @@ func (e *Engine) retrieveData(domain, entity, aspect string) ([]byte, error) {
 	nc.SetAspect(dflags, aspect)
 	nc.SetTag(dflags, "data")
 	nc.SetTimestamp(dflags, "latest")
+	if pubKey != "" {
+		nc.SetEncryptDataPKey(dflags, pubKey)
+	}
 	
+	span := e.clientSpan(entity, "natsclient.Get", storageAttrs(domain, entity, aspect)...)
+	start := time.Now()
 	rsp := nc.Get(e.Server, dflags, e.Token)
+	e.Metrics.observeNATS("get", start, rsp.Header.Status, false)
+	span.SetAttributes(attribute.Int("nats.status", rsp.Header.Status))
+	if rsp.Header.Status == http.StatusNotFound {
+		err := fmt.Errorf("retrieve %s/%s/%s: %w", domain, entity, aspect, ErrNotFound)
+		endClientSpan(span, err)
+		return nil, err
+	}
 	if rsp.Header.Status != http.StatusOK {
-		return nil, fmt.Errorf("retrieve %s/%s/%s failed: %s (status %d)",
+		err := fmt.Errorf("retrieve %s/%s/%s failed: %s (status %d)",
 			domain, entity, aspect, rsp.Header.ErrorStr, rsp.Header.Status)
+		endClientSpan(span, err)
+		return nil, err
 	}
+	endClientSpan(span, nil)
 	return rsp.Response, nil
 }
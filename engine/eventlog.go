@@ -0,0 +1,356 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// EVENT SOURCING (Sections 4.5, 4.6)
+// Documents such as Tasks/{id}/spec are overwritten in place, so every engine
+// mutation is also appended as a typed DomainEvent. Each engine appends to its
+// own stream, Events/log/event_{engine}_{n} with head_{engine}, so engines
+// never contend for a sequence; readers merge the streams in time order.
+// Each engine also keeps, per task or agent stream, the sequences it wrote
+// for that stream (Events/{stream}/index_{engine}), so a query about one task
+// reads only that task's events instead of every engine's whole history.
+// The event is appended before the document it describes is written: a crash
+// in between leaves the log ahead of the store, never behind it.
+// ProjectState folds the log up to any instant into task, contract, bid and
+// reputation views; RestoreFromLog writes such a projection back after
+// corruption without appending to the log again.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+const eventLogEntity = "log"
+
+// recordDomainEvent appends a mutation to this engine's event stream.
+func (e *Engine) recordDomainEvent(kind t.DomainEventType, streamID string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", kind, err)
+	}
+	
+	e.eventMu.Lock()
+	defer e.eventMu.Unlock()
+	
+	if err := e.registerWriter(DomainEvents, eventLogEntity, "head"); err != nil {
+		return err
+	}
+	head, err := e.eventStreamHead(e.SelfID)
+	if err != nil {
+		return err
+	}
+	event := t.DomainEvent{
+		Sequence: head + 1,
+		Type:     kind,
+		StreamID: streamID,
+		ActorID:  e.SelfID,
+		Data:     data,
+		At:       time.Now(),
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := e.storeData(DomainEvents, eventLogEntity, eventAspect(e.SelfID, event.Sequence), body); err != nil {
+		return fmt.Errorf("append %s event: %w", kind, err)
+	}
+	if err := e.indexStreamEvent(streamID, event.Sequence); err != nil {
+		return fmt.Errorf("index %s event: %w", kind, err)
+	}
+	
+	body, err = json.Marshal(event.Sequence)
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainEvents, eventLogEntity, e.shardAspect("head"), body)
+}
+
+// GetEventLog returns the log in sequence order, limited to one task or
+// agent stream unless streamID is empty.
+func (e *Engine) GetEventLog(streamID string) ([]t.DomainEvent, error) {
+	if streamID == "" {
+		return e.readEventLog(time.Time{})
+	}
+	return e.readStreamEvents(streamID, time.Time{})
+}
+
+// ProjectState rebuilds task, contract, bid and reputation views from the
+// events recorded at or before asOf. A zero asOf replays the whole log.
+func (e *Engine) ProjectState(asOf time.Time) (*t.StateProjection, error) {
+	events, err := e.readEventLog(asOf)
+	if err != nil {
+		return nil, err
+	}
+	return projectEvents(events, asOf)
+}
+
+// ProjectTask returns a task as the log knew it at asOf. Only the task's own
+// stream is replayed: its creation, updates and decomposition are all
+// recorded under the task ID.
+func (e *Engine) ProjectTask(taskID string, asOf time.Time) (*t.TaskSpec, error) {
+	events, err := e.readStreamEvents(taskID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	p, err := projectEvents(events, asOf)
+	if err != nil {
+		return nil, err
+	}
+	task, ok := p.Tasks[taskID]
+	if !ok {
+		return nil, fmt.Errorf("task %s not in the event log as of %s", taskID, asOf.Format(time.RFC3339))
+	}
+	return &task, nil
+}
+
+// projectEvents folds events, already in time order, into a projection.
+func projectEvents(events []t.DomainEvent, asOf time.Time) (*t.StateProjection, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	
+	p := &t.StateProjection{
+		AsOf:       asOf,
+		Through:    make(map[string]uint64),
+		Tasks:      make(map[string]t.TaskSpec),
+		Contracts:  make(map[string]t.DelegationContract),
+		Bids:       make(map[string][]t.Bid),
+		Latest:     make(map[string]t.MonitorEvent),
+		Reputation: make(map[string]t.ReputationView),
+	}
+	for _, event := range events {
+		if err := applyDomainEvent(p, event); err != nil {
+			return nil, fmt.Errorf("apply event %d: %w", event.Sequence, err)
+		}
+		p.Through[event.ActorID] = event.Sequence
+	}
+	for id, view := range p.Reputation {
+		view.TrustScore = trustScore(view.Records, asOf)
+		p.Reputation[id] = view
+	}
+	return p, nil
+}
+
+// RestoreFromLog overwrites stored task specs and contracts with their state
+// projected at asOf (zero for the latest), re-indexing both catalogs. The
+// writes are not themselves logged, so replaying again gives the same result.
+func (e *Engine) RestoreFromLog(asOf time.Time) (*t.StateProjection, error) {
+	p, err := e.ProjectState(asOf)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range p.Tasks {
+		if err := e.writeTask(task); err != nil {
+			return nil, fmt.Errorf("restore task %s: %w", task.TaskID, err)
+		}
+	}
+	for _, contract := range p.Contracts {
+		contract := contract
+		if err := e.writeContract(&contract); err != nil {
+			return nil, fmt.Errorf("restore contract %s: %w", contract.ContractID, err)
+		}
+	}
+	return p, nil
+}
+
+// applyDomainEvent folds one event into a projection.
+func applyDomainEvent(p *t.StateProjection, event t.DomainEvent) error {
+	switch event.Type {
+	case t.DomainTaskCreated, t.DomainTaskUpdated:
+		var task t.TaskSpec
+		if err := json.Unmarshal(event.Data, &task); err != nil {
+			return err
+		}
+		p.Tasks[task.TaskID] = task
+	
+	case t.DomainTaskDecomposed:
+		var d t.TaskDecomposition
+		if err := json.Unmarshal(event.Data, &d); err != nil {
+			return err
+		}
+		if parent, ok := p.Tasks[d.ParentTaskID]; ok {
+			parent.SubTaskIDs = d.SubTaskIDs
+			parent.IsLeaf = false
+			p.Tasks[d.ParentTaskID] = parent
+		}
+	
+	case t.DomainBidSubmitted:
+		var bid t.Bid
+		if err := json.Unmarshal(event.Data, &bid); err != nil {
+			return err
+		}
+		p.Bids[bid.TaskID] = append(p.Bids[bid.TaskID], bid)
+	
+	case t.DomainBidAccepted, t.DomainContractUpdated:
+		var contract t.DelegationContract
+		if err := json.Unmarshal(event.Data, &contract); err != nil {
+			return err
+		}
+		p.Contracts[contract.ContractID] = contract
+	
+	case t.DomainMonitorRecorded:
+		var m t.MonitorEvent
+		if err := json.Unmarshal(event.Data, &m); err != nil {
+			return err
+		}
+		p.Latest[m.TaskID] = m
+	
+	case t.DomainReputationRecord:
+		var record t.ReputationRecord
+		if err := json.Unmarshal(event.Data, &record); err != nil {
+			return err
+		}
+		view := p.Reputation[record.AgentID]
+		view.AgentID = record.AgentID
+		view.Records = append(view.Records, record)
+		p.Reputation[record.AgentID] = view
+	
+	case t.DomainTriggerRaised, t.DomainTaskVerified:
+		// Their effects arrive as task, contract and reputation events;
+		// they stay in the log as the record of why
+	
+	default:
+		return fmt.Errorf("unknown domain event type %q", event.Type)
+	}
+	return nil
+}
+
+// readEventLog merges every engine's stream in time order, stopping after asOf
+// unless it is zero.
+func (e *Engine) readEventLog(asOf time.Time) ([]t.DomainEvent, error) {
+	writers, err := e.shardWriters(DomainEvents, eventLogEntity, "head")
+	if err != nil {
+		return nil, err
+	}
+	var events []t.DomainEvent
+	for _, w := range writers {
+		stream, err := e.readEventStream(w, asOf)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, stream...)
+	}
+	sortDomainEvents(events)
+	return events, nil
+}
+
+// readStreamEvents merges one task or agent stream's events from every engine
+// through the per-stream indexes, stopping after asOf unless it is zero.
+func (e *Engine) readStreamEvents(streamID string, asOf time.Time) ([]t.DomainEvent, error) {
+	writers, err := e.shardWriters(DomainEvents, streamID, "index")
+	if err != nil {
+		return nil, err
+	}
+	var events []t.DomainEvent
+	for _, w := range writers {
+		head, err := e.eventStreamHead(w)
+		if err != nil {
+			return nil, err
+		}
+		var seqs []uint64
+		if _, err := e.retrieveJSON(DomainEvents, streamID, "index_"+w, &seqs); err != nil {
+			return nil, fmt.Errorf("stream index of %s by %s: %w", streamID, w, err)
+		}
+		for _, seq := range seqs {
+			// A sequence past the head was indexed by an append that did not
+			// complete, and its slot may since hold another stream's event
+			if seq > head {
+				continue
+			}
+			event, err := e.readEvent(w, seq)
+			if err != nil {
+				return nil, err
+			}
+			if event.StreamID != streamID || (!asOf.IsZero() && event.At.After(asOf)) {
+				continue
+			}
+			events = append(events, *event)
+		}
+	}
+	sortDomainEvents(events)
+	return events, nil
+}
+
+// indexStreamEvent adds a sequence to this engine's index of a stream.
+func (e *Engine) indexStreamEvent(streamID string, seq uint64) error {
+	if err := e.registerWriter(DomainEvents, streamID, "index"); err != nil {
+		return err
+	}
+	var seqs []uint64
+	if _, err := e.retrieveJSON(DomainEvents, streamID, e.shardAspect("index"), &seqs); err != nil {
+		return err
+	}
+	body, err := json.Marshal(append(seqs, seq))
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainEvents, streamID, e.shardAspect("index"), body)
+}
+
+// sortDomainEvents orders merged streams by time, then engine and sequence.
+func sortDomainEvents(events []t.DomainEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if !a.At.Equal(b.At) {
+			return a.At.Before(b.At)
+		}
+		if a.ActorID != b.ActorID {
+			return a.ActorID < b.ActorID
+		}
+		return a.Sequence < b.Sequence
+	})
+}
+
+// readEventStream loads one engine's events in order, stopping after asOf
+// unless it is zero.
+func (e *Engine) readEventStream(writer string, asOf time.Time) ([]t.DomainEvent, error) {
+	head, err := e.eventStreamHead(writer)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]t.DomainEvent, 0, head)
+	for seq := uint64(1); seq <= head; seq++ {
+		event, err := e.readEvent(writer, seq)
+		if err != nil {
+			return nil, err
+		}
+		if !asOf.IsZero() && event.At.After(asOf) {
+			break
+		}
+		events = append(events, *event)
+	}
+	return events, nil
+}
+
+// readEvent loads one event from an engine's stream.
+func (e *Engine) readEvent(writer string, seq uint64) (*t.DomainEvent, error) {
+	data, err := e.retrieveData(DomainEvents, eventLogEntity, eventAspect(writer, seq))
+	if err != nil {
+		return nil, fmt.Errorf("read event %s/%d: %w", writer, seq, err)
+	}
+	var event t.DomainEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("unmarshal event %s/%d: %w", writer, seq, err)
+	}
+	return &event, nil
+}
+
+// eventStreamHead returns the last sequence an engine wrote, or 0 for a stream
+// it has not started. Read failures are returned: guessing 0 would overwrite
+// the stream from the start.
+func (e *Engine) eventStreamHead(writer string) (uint64, error) {
+	var head uint64
+	if _, err := e.retrieveJSON(DomainEvents, eventLogEntity, "head_"+writer, &head); err != nil {
+		return 0, fmt.Errorf("event stream head of %s: %w", writer, err)
+	}
+	return head, nil
+}
+
+func eventAspect(writer string, seq uint64) string {
+	return fmt.Sprintf("event_%s_%d", writer, seq)
+}
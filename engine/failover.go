@@ -0,0 +1,233 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+	
+	opt "github.com/dataparency-dev/AI-delegation/optomizer"
+	t "github.com/dataparency-dev/AI-delegation/types"
+	"github.com/nats-io/nats.go"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// BACKUP AGENT FAILOVER (Section 4.4)
+// Fast-path re-delegation: the contract's backup agent is offered the task over
+// a request/reply handshake on the agent channel before the market is re-opened.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// selectBackupAgent ranks the task's bid book and returns the best-scoring
+// agent other than the winner. Returns "" when there is no runner-up.
+func (e *Engine) selectBackupAgent(task *t.TaskSpec, winner t.Bid) string {
+	bids, err := e.GetBids(task.TaskID)
+	if err != nil || len(bids) < 2 {
+		return ""
+	}
+	
+	trustMap := make(map[string]float64, len(bids))
+	capsMap := make(map[string][]string, len(bids))
+	for _, b := range bids {
+		if agent, err := e.GetAgent(b.AgentID); err == nil {
+			trustMap[b.AgentID] = agent.TrustScore
+			capsMap[b.AgentID] = agent.Capabilities
+		} else {
+			capsMap[b.AgentID] = b.Capabilities
+		}
+	}
+	
+	ranked := opt.RankBids(bids, opt.SelectWeightsForTask(*task), trustMap, task.RequiredCapabilities, capsMap)
+	for _, sb := range ranked {
+		if sb.Bid.AgentID != winner.AgentID {
+			return sb.Bid.AgentID
+		}
+	}
+	return ""
+}
+
+// failoverToBackup offers the task to its contract's backup agent. On acceptance
+// the contract and permissions are moved over and true is returned; false means
+// the caller should fall back to re-bidding.
+func (e *Engine) failoverToBackup(task *t.TaskSpec) (bool, error) {
+	if task.ContractID == "" {
+		return false, nil
+	}
+	contract, err := e.GetContract(task.ContractID)
+	if err != nil {
+		return false, fmt.Errorf("get contract %s: %w", task.ContractID, err)
+	}
+	backupID := contract.BackupAgentID
+	if backupID == "" || backupID == contract.DelegateeID {
+		return false, nil
+	}
+	
+	// Skip the round-trip if the backup is known to be unavailable, including
+	// one that has gone quiet since its status was last written
+	backup, err := e.GetAgent(backupID)
+	if err != nil {
+		return false, fmt.Errorf("get backup agent %s: %w", backupID, err)
+	}
+	if backup.Status == t.StatusOffline || e.missedHeartbeats(backupID) ||
+		(backup.MaxLoad > 0 && backup.CurrentLoad >= backup.MaxLoad) {
+		log.Printf("Backup %s unavailable for task %s (status=%s missed_beats=%v load=%d/%d)",
+			backupID, task.TaskID, backup.Status, e.missedHeartbeats(backupID), backup.CurrentLoad, backup.MaxLoad)
+		return false, nil
+	}
+	
+	resume, err := e.LatestVerifiedCheckpoint(task.TaskID)
+	if err != nil {
+		log.Printf("Checkpoint lookup for task %s: %v", task.TaskID, err)
+	}
+	
+	offer := t.FailoverOffer{
+		TaskID:      task.TaskID,
+		ContractID:  contract.ContractID,
+		DelegatorID: e.SelfID,
+		PrevAgentID: contract.DelegateeID,
+		Terms:       contract.Terms,
+		Permissions: contract.Permissions,
+		Reason:      "primary delegatee failed",
+		ResumeFrom:  resume,
+		OfferedAt:   time.Now(),
+	}
+	reply, err := e.sendFailoverOffer(backupID, offer)
+	if err != nil {
+		return false, err
+	}
+	if !reply.Accepted {
+		log.Printf("Backup %s declined task %s: %s", backupID, task.TaskID, reply.Reason)
+		return false, nil
+	}
+	
+	if err := e.transferContract(task, contract, backupID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sendFailoverOffer performs the request/reply handshake with the backup agent.
+func (e *Engine) sendFailoverOffer(backupID string, offer t.FailoverOffer) (*t.FailoverReply, error) {
+	channelName, err := e.SetupAgentChannel(offer.TaskID, backupID)
+	if err != nil {
+		return nil, fmt.Errorf("agent channel for %s: %w", backupID, err)
+	}
+	
+	data, err := e.requestAgentMessage(channelName, offer.TaskID, t.MsgFailoverOffer, offer, e.FailoverTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s did not answer: %w", backupID, err)
+	}
+	
+	var reply t.FailoverReply
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, fmt.Errorf("unmarshal failover reply: %w", err)
+	}
+	return &reply, nil
+}
+
+// transferContract closes the failed delegatee's contract, opens an equivalent
+// one for the new delegatee and moves the task's permissions and its slot in
+// the agents' recorded load across.
+func (e *Engine) transferContract(task *t.TaskSpec, old *t.DelegationContract, newAgentID string) error {
+	chain, err := e.extendChain(task, newAgentID, contractID(task.TaskID, newAgentID))
+	if err != nil {
+		return err
+	}
+	
+	old.Status = t.ContractBreached
+	if err := e.storeContract(old); err != nil {
+		return fmt.Errorf("close contract %s: %w", old.ContractID, err)
+	}
+	
+	now := time.Now()
+	contract := &t.DelegationContract{
+		ContractID:  contractID(task.TaskID, newAgentID),
+		TaskID:      task.TaskID,
+		DelegatorID: old.DelegatorID,
+		DelegateeID: newAgentID,
+		AcceptedBid: e.backupBid(task.TaskID, newAgentID, old),
+		Terms:       old.Terms,
+		Status:      t.ContractActive,
+		Permissions: old.Permissions,
+		Version:     1,
+		CreatedAt:   now,
+		SignedAt:    &now,
+	}
+	if err := e.storeContract(contract); err != nil {
+		return err
+	}
+	
+	for _, perm := range old.Permissions {
+		if err := e.RevokePermission(old.DelegateeID, perm.Resource); err != nil {
+			log.Printf("Revoke %s from %s: %v", perm.Resource, old.DelegateeID, err)
+		}
+		if len(perm.Operations) == 0 {
+			continue
+		}
+		perm.GrantedBy = e.SelfID
+		if err := e.GrantPermission(newAgentID, perm.Resource, perm); err != nil {
+			return fmt.Errorf("grant %s to %s: %w", perm.Resource, newAgentID, err)
+		}
+	}
+	
+	task.DelegateeID = newAgentID
+	task.ContractID = contract.ContractID
+	task.DelegationChain = chain
+	task.Status = t.TaskAssigned
+	task.StartedAt = &now
+	if err := e.UpdateTask(*task); err != nil {
+		return err
+	}
+	if err := e.adjustAgentLoad(old.DelegateeID, -1); err != nil {
+		log.Printf("Release load of %s: %v", old.DelegateeID, err)
+	}
+	if err := e.adjustAgentLoad(newAgentID, 1); err != nil {
+		log.Printf("Record load of %s: %v", newAgentID, err)
+	}
+	if err := e.handOffCheckpoint(task); err != nil {
+		log.Printf("Resume checkpoint for task %s: %v", task.TaskID, err)
+	}
+	
+	log.Printf("FAILOVER: task %s moved %s → %s (contract %s)",
+		task.TaskID, old.DelegateeID, newAgentID, contract.ContractID)
+	return nil
+}
+
+// backupBid returns the bid the backup agent placed on the task, so settlement
+// bills the price it offered. If the bid cannot be found the failed contract's
+// accepted bid stands in, rather than leaving the agreed price at zero.
+func (e *Engine) backupBid(taskID, agentID string, old *t.DelegationContract) *t.Bid {
+	bids, err := e.GetBids(taskID)
+	if err != nil {
+		log.Printf("Bids for task %s: %v", taskID, err)
+	}
+	for i := range bids {
+		if bids[i].AgentID == agentID {
+			return &bids[i]
+		}
+	}
+	log.Printf("No bid from backup %s on task %s; keeping the original agreed price", agentID, taskID)
+	return old.AcceptedBid
+}
+
+// ServeFailoverOffers is the backup agent's side of the handshake. It listens on
+// the agent channel for the given task and answers each offer with decide's verdict.
+func (e *Engine) ServeFailoverOffers(taskID, delegatorID string, decide func(t.FailoverOffer) t.FailoverReply) error {
+	channelName := agentChannelName(taskID, delegatorID, e.SelfID)
+	return e.subscribeAgentChannel(channelName, "failover", func(env t.AgentMessage, msg *nats.Msg) {
+		if env.Type != t.MsgFailoverOffer {
+			return
+		}
+		var offer t.FailoverOffer
+		if err := json.Unmarshal(env.Payload, &offer); err != nil {
+			log.Printf("Malformed failover offer on %s: %v", channelName, err)
+			return
+		}
+		reply := decide(offer)
+		reply.TaskID = offer.TaskID
+		reply.AgentID = e.SelfID
+		body, _ := json.Marshal(reply)
+		if err := msg.Respond(body); err != nil {
+			log.Printf("Failover reply on %s: %v", channelName, err)
+		}
+	})
+}
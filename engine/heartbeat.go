@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+	nc "github.com/dataparency-dev/natsclient"
+	"github.com/nats-io/nats.go"
+)
+
+// HeartbeatChannel prefixes the per-agent secure channels agents publish
+// liveness beacons on.
+const HeartbeatChannel = "agent_heartbeats"
+
+const (
+	DefaultHeartbeatInterval = 30 * time.Second
+	DefaultMissedBeats       = 3
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// AGENT LIVENESS (Sections 4.2, 4.4)
+// Each agent publishes heartbeats on its own secure channel, so a beat is only
+// accepted for the agent whose channel carried it; the engine records each beat
+// in the agent's LastSeenAt and Status. Liveness tracking starts with an
+// agent's first heartbeat. An agent that then misses MissedBeats intervals is
+// marked offline, dropped from capability search, and its active tasks get an
+// agent_unresponsive trigger.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// SendHeartbeat publishes one heartbeat for this engine's agent.
+func (e *Engine) SendHeartbeat(hb t.Heartbeat) error {
+	channelName := heartbeatChannelName(e.SelfID)
+	rdid, _ := nc.RelationRetrieve(e.Server, channelName, e.Token)
+	if rdid == "" {
+		return fmt.Errorf("no heartbeat channel %s", channelName)
+	}
+	hb.AgentID = e.SelfID
+	hb.SentAt = time.Now()
+	body, err := json.Marshal(hb)
+	if err != nil {
+		return err
+	}
+	return nc.SecureChannelPublish(body, e.Server, channelName, e.Token, rdid, int64(e.heartbeatTimeout().Seconds()))
+}
+
+// heartbeatChannelName is the channel one agent's heartbeats travel on.
+func heartbeatChannelName(agentID string) string {
+	return fmt.Sprintf("%s_%s", HeartbeatChannel, agentID)
+}
+
+// StartHeartbeat sends a heartbeat every HeartbeatInterval until stop is called.
+// report, if set, supplies the status and load for each beat.
+func (e *Engine) StartHeartbeat(report func() t.Heartbeat) (stop func()) {
+	var seq uint64
+	beat := func() {
+		var hb t.Heartbeat
+		if report != nil {
+			hb = report()
+		}
+		hb.Sequence = atomic.AddUint64(&seq, 1)
+		if err := e.SendHeartbeat(hb); err != nil {
+			log.Printf("Heartbeat from %s: %v", e.SelfID, err)
+		}
+	}
+	beat()
+	return runEvery(e.heartbeatInterval(), beat)
+}
+
+// WatchHeartbeats opens the heartbeat channel of each agent and records every
+// beat received. Each engine listens in its own queue group so it sees every beat.
+func (e *Engine) WatchHeartbeats(agentIDs ...string) error {
+	for _, agentID := range agentIDs {
+		if err := e.watchHeartbeat(agentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Engine) watchHeartbeat(agentID string) error {
+	channelName := heartbeatChannelName(agentID)
+	rdid, err := nc.InitChannel(e.Server, channelName, e.Token, true)
+	if err != nil {
+		return fmt.Errorf("init heartbeat channel for %s: %w", agentID, err)
+	}
+	
+	_, err = nc.SecureChannelQueueSubscribe(
+		e.Server, channelName, fmt.Sprintf("liveness_%s", e.SelfID), e.Token, rdid,
+		func(msg *nats.Msg) {
+			var hb t.Heartbeat
+			if err := json.Unmarshal(msg.Data, &hb); err != nil {
+				log.Printf("Malformed heartbeat on %s: %v", channelName, err)
+				return
+			}
+			if err := e.RecordHeartbeat(agentID, hb); err != nil {
+				log.Printf("Record heartbeat from %s: %v", agentID, err)
+			}
+		},
+	)
+	return err
+}
+
+// RecordHeartbeat applies one heartbeat received on agentID's channel to the
+// agent's profile. A beat claiming to be from another agent is refused, so one
+// agent cannot keep another looking alive.
+func (e *Engine) RecordHeartbeat(agentID string, hb t.Heartbeat) error {
+	if hb.AgentID != agentID {
+		return fmt.Errorf("heartbeat on %s's channel claims to be from %s", agentID, hb.AgentID)
+	}
+	agent, err := e.GetAgent(hb.AgentID)
+	if err != nil {
+		return fmt.Errorf("get agent %s: %w", hb.AgentID, err)
+	}
+	
+	now := time.Now()
+	e.noteAgentSeen(hb.AgentID, now)
+	
+	switch {
+	case hb.Status != "":
+		agent.Status = hb.Status
+	case agent.Status == t.StatusOffline:
+		agent.Status = t.StatusOnline
+		log.Printf("Agent %s back online", hb.AgentID)
+	}
+	if hb.CurrentLoad != nil {
+		agent.CurrentLoad = *hb.CurrentLoad
+	}
+	agent.LastSeenAt = now
+	return e.saveAgent(*agent)
+}
+
+// CheckLiveness marks agents offline once they have missed MissedBeats
+// heartbeats and raises agent_unresponsive on their active tasks. Returns the
+// agents newly marked offline.
+func (e *Engine) CheckLiveness() []string {
+	timeout := e.heartbeatTimeout()
+	now := time.Now()
+	
+	e.beatMu.Lock()
+	var silent []string
+	for id, seen := range e.lastBeat {
+		if now.Sub(seen) > timeout {
+			silent = append(silent, id)
+		}
+	}
+	e.beatMu.Unlock()
+	
+	var offline []string
+	for _, id := range silent {
+		agent, err := e.GetAgent(id)
+		if err != nil || agent.Status == t.StatusOffline {
+			continue
+		}
+		agent.Status = t.StatusOffline
+		if err := e.saveAgent(*agent); err != nil {
+			log.Printf("Mark %s offline: %v", id, err)
+			continue
+		}
+		offline = append(offline, id)
+		log.Printf("Agent %s OFFLINE: no heartbeat since %s", id, agent.LastSeenAt.Format(time.RFC3339))
+		e.raiseUnresponsive(agent)
+	}
+	return offline
+}
+
+// StartLivenessChecks runs CheckLiveness every HeartbeatInterval until stop is called.
+func (e *Engine) StartLivenessChecks() (stop func()) {
+	return runEvery(e.heartbeatInterval(), func() { e.CheckLiveness() })
+}
+
+// raiseUnresponsive raises agent_unresponsive on every task the agent holds
+// for this engine. Other delegators watch the agent themselves and decide
+// what happens to their own tasks.
+func (e *Engine) raiseUnresponsive(agent *t.AgentProfile) {
+	tasks, err := e.agentInFlight(agent.AgentID)
+	if err != nil {
+		log.Printf("Active tasks of %s: %v", agent.AgentID, err)
+		return
+	}
+	for _, task := range delegatedBy(tasks, e.SelfID) {
+		err := e.RaiseTrigger(t.AdaptiveTrigger{
+			TriggerID:   fmt.Sprintf("liveness_%s_%s_%d", agent.AgentID, task.TaskID, time.Now().UnixNano()),
+			TaskID:      task.TaskID,
+			Type:        t.TriggerIntUnresponsive,
+			AgentID:     agent.AgentID,
+			Description: fmt.Sprintf("missed %d heartbeats (last seen %s)", e.missedBeatLimit(), agent.LastSeenAt.Format(time.RFC3339)),
+		})
+		if err != nil {
+			log.Printf("Raise unresponsive trigger on %s: %v", task.TaskID, err)
+		}
+	}
+}
+
+// noteAgentSeen records a sign of life for liveness tracking.
+func (e *Engine) noteAgentSeen(agentID string, at time.Time) {
+	e.beatMu.Lock()
+	defer e.beatMu.Unlock()
+	if e.lastBeat == nil {
+		e.lastBeat = make(map[string]time.Time)
+	}
+	e.lastBeat[agentID] = at
+}
+
+// missedHeartbeats reports whether a tracked agent is past its heartbeat
+// timeout. Agents this engine has never had a heartbeat from are not judged.
+func (e *Engine) missedHeartbeats(agentID string) bool {
+	e.beatMu.Lock()
+	seen, ok := e.lastBeat[agentID]
+	e.beatMu.Unlock()
+	return ok && time.Since(seen) > e.heartbeatTimeout()
+}
+
+func (e *Engine) heartbeatInterval() time.Duration {
+	if e.HeartbeatInterval <= 0 {
+		return DefaultHeartbeatInterval
+	}
+	return e.HeartbeatInterval
+}
+
+func (e *Engine) missedBeatLimit() int {
+	if e.MissedBeats <= 0 {
+		return DefaultMissedBeats
+	}
+	return e.MissedBeats
+}
+
+// heartbeatTimeout is how long an agent may stay silent before it is offline.
+func (e *Engine) heartbeatTimeout() time.Duration {
+	return e.heartbeatInterval() * time.Duration(e.missedBeatLimit())
+}
@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+	nc "github.com/dataparency-dev/natsclient"
+	"github.com/nats-io/nats.go"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// LIVE MONITORING AGGREGATOR (Section 4.5)
+// Folds each task's monitoring stream into live state: progress, spend against
+// budget, burn rate and a projected finish against the deadline. Events this
+// engine emits are observed directly; WatchTask adds tasks monitored elsewhere.
+// A task's state is dropped once it is verified, failed or cancelled.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// liveTask is the raw per-task state kept between events.
+type liveTask struct {
+	agentID       string
+	lastType      t.MonitorEventType
+	events        int
+	firstAt       time.Time
+	firstProgress float64
+	firstSpend    float64
+	lastAt        time.Time
+	progress      float64
+	spend         float64
+}
+
+// observeEvent folds an event into the live state and, if a detector is
+// attached, checks it for anomalies. Copies with progress or spend withheld
+// are not checked, since their zeros would read as regressions.
+func (e *Engine) observeEvent(event t.MonitorEvent) {
+	withheld := event.Withholds(FieldProgress) || event.Withholds(FieldResourceUse)
+	if e.foldEvent(event) && e.Detector != nil && !withheld {
+		e.detectAnomalies(event)
+	}
+}
+
+// foldEvent updates the live state and reports whether the event was new.
+// Events older than the latest seen for the task are ignored, so an event
+// received both locally and from the channel is counted once.
+func (e *Engine) foldEvent(event t.MonitorEvent) bool {
+	e.liveMu.Lock()
+	defer e.liveMu.Unlock()
+	
+	if e.live == nil {
+		e.live = make(map[string]*liveTask)
+	}
+	// A cancelled task is finished; its notice must not start new state
+	if event.EventType == t.EventTaskCancelled {
+		delete(e.live, event.TaskID)
+		return false
+	}
+	lt, ok := e.live[event.TaskID]
+	if !ok {
+		lt = &liveTask{
+			firstAt:       event.Timestamp,
+			firstProgress: event.Progress,
+			firstSpend:    event.ResourceUse,
+		}
+		e.live[event.TaskID] = lt
+	} else if !event.Timestamp.After(lt.lastAt) {
+		return false
+	}
+	
+	lt.events++
+	lt.agentID = event.AgentID
+	lt.lastType = event.EventType
+	lt.lastAt = event.Timestamp
+	// Redacted copies read zero for withheld fields; keep the last known value
+	if !event.Withholds(FieldProgress) {
+		lt.progress = event.Progress
+	}
+	// Notices such as checkpoints and cancellations carry no spend figure
+	if (event.ResourceUse > 0 || event.EventType == t.EventProgressUpdate) && !event.Withholds(FieldResourceUse) {
+		lt.spend = event.ResourceUse
+	}
+	return true
+}
+
+// WatchTask subscribes the aggregator to a task's monitoring channel. Each
+// engine listens in its own queue group so it sees every event.
+func (e *Engine) WatchTask(taskID string) error {
+	channelName := fmt.Sprintf("monitor_%s", taskID)
+	rdid, _ := nc.RelationRetrieve(e.Server, channelName, e.Token)
+	if rdid == "" {
+		return fmt.Errorf("no monitoring channel for task %s", taskID)
+	}
+	
+	_, err := nc.SecureChannelQueueSubscribe(
+		e.Server, channelName, fmt.Sprintf("live_%s", e.SelfID), e.Token, rdid,
+		func(msg *nats.Msg) {
+			var event t.MonitorEvent
+			if err := json.Unmarshal(msg.Data, &event); err != nil {
+				log.Printf("Malformed monitoring event on %s: %v", channelName, err)
+				return
+			}
+			e.receiveEvent(event)
+		},
+	)
+	return err
+}
+
+// GetLiveState returns the aggregator's current view of a task.
+func (e *Engine) GetLiveState(taskID string) (*t.TaskLiveState, error) {
+	e.liveMu.RLock()
+	lt, ok := e.live[taskID]
+	var raw liveTask
+	if ok {
+		raw = *lt
+	}
+	e.liveMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no monitoring events observed for task %s", taskID)
+	}
+	
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return deriveLiveState(task, &raw, time.Now()), nil
+}
+
+// forgetLive drops a finished task from the aggregator and the silence tracker.
+func (e *Engine) forgetLive(taskID string) {
+	e.liveMu.Lock()
+	delete(e.live, taskID)
+	e.liveMu.Unlock()
+	
+	e.reportMu.Lock()
+	delete(e.silent, taskID)
+	e.reportMu.Unlock()
+}
+
+// ListLiveStates returns the live state of every task the aggregator is
+// tracking, ordered by task ID. A task that cannot be read is left out and
+// its error joined into the one returned alongside the other states.
+func (e *Engine) ListLiveStates() ([]t.TaskLiveState, error) {
+	e.liveMu.RLock()
+	ids := make([]string, 0, len(e.live))
+	for id := range e.live {
+		ids = append(ids, id)
+	}
+	e.liveMu.RUnlock()
+	sort.Strings(ids)
+	
+	states := make([]t.TaskLiveState, 0, len(ids))
+	var errs []error
+	for _, id := range ids {
+		state, err := e.GetLiveState(id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("live state of %s: %w", id, err))
+			continue
+		}
+		states = append(states, *state)
+	}
+	return states, errors.Join(errs...)
+}
+
+// deriveLiveState computes rates and projections. Rates are measured from the
+// task's start when known, otherwise from the first observed event.
+func deriveLiveState(task *t.TaskSpec, lt *liveTask, now time.Time) *t.TaskLiveState {
+	state := &t.TaskLiveState{
+		TaskID:         task.TaskID,
+		AgentID:        lt.agentID,
+		LastEventType:  lt.lastType,
+		Events:         lt.events,
+		Progress:       lt.progress,
+		Spend:          lt.spend,
+		MaxBudget:      task.MaxBudget,
+		FirstEventAt:   lt.firstAt,
+		LastEventAt:    lt.lastAt,
+		SinceLastEvent: now.Sub(lt.lastAt).Seconds(),
+		Deadline:       task.Deadline,
+	}
+	if task.MaxBudget > 0 {
+		state.BudgetUsed = lt.spend / task.MaxBudget
+	}
+	if lt.progress > 0 {
+		state.ProjectedSpend = lt.spend / lt.progress
+	}
+	
+	originAt, originProgress, originSpend := lt.firstAt, lt.firstProgress, lt.firstSpend
+	if task.StartedAt != nil && task.StartedAt.Before(lt.firstAt) {
+		originAt, originProgress, originSpend = *task.StartedAt, 0, 0
+	}
+	hours := lt.lastAt.Sub(originAt).Hours()
+	if hours <= 0 {
+		return state
+	}
+	state.BurnRate = (lt.spend - originSpend) / hours
+	state.ProgressRate = (lt.progress - originProgress) / hours
+	
+	if lt.progress >= 1 {
+		eta := lt.lastAt
+		state.ETA = &eta
+	} else if state.ProgressRate > 0 {
+		remaining := (1 - lt.progress) / state.ProgressRate
+		eta := lt.lastAt.Add(time.Duration(remaining * float64(time.Hour)))
+		state.ETA = &eta
+	}
+	if state.ETA != nil && task.Deadline != nil {
+		slack := task.Deadline.Sub(*state.ETA).Seconds()
+		state.DeadlineSlack = &slack
+	}
+	return state
+}
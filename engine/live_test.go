@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+	
+	"github.com/dataparency-dev/AI-delegation/types"
+)
+
+func TestDeriveLiveState(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(h float64) time.Time { return t0.Add(time.Duration(h * float64(time.Hour))) }
+	ptr := func(v time.Time) *time.Time { return &v }
+	deadline := at(10)
+	
+	tests := []struct {
+		name      string
+		started   *time.Time
+		lt        liveTask
+		burnRate  float64
+		progRate  float64
+		eta       *time.Time
+		slackSecs *float64
+	}{
+		{
+			name:     "rates from the first event",
+			lt:       liveTask{firstAt: t0, firstProgress: 0.1, firstSpend: 10, lastAt: at(2), progress: 0.5, spend: 50},
+			burnRate: 20, progRate: 0.2, eta: ptr(at(4.5)), slackSecs: floatPtr(5.5 * 3600),
+		},
+		{
+			name:     "rates from the task's start",
+			started:  ptr(at(-2)),
+			lt:       liveTask{firstAt: t0, firstProgress: 0.1, firstSpend: 10, lastAt: at(2), progress: 0.5, spend: 50},
+			burnRate: 12.5, progRate: 0.125, eta: ptr(at(6)), slackSecs: floatPtr(4 * 3600),
+		},
+		{
+			name:     "start after the first event is ignored",
+			started:  ptr(at(1)),
+			lt:       liveTask{firstAt: t0, firstProgress: 0.1, firstSpend: 10, lastAt: at(2), progress: 0.5, spend: 50},
+			burnRate: 20, progRate: 0.2, eta: ptr(at(4.5)), slackSecs: floatPtr(5.5 * 3600),
+		},
+		{
+			name:     "finished",
+			lt:       liveTask{firstAt: t0, lastAt: at(4), progress: 1, spend: 80},
+			burnRate: 20, progRate: 0.25, eta: ptr(at(4)), slackSecs: floatPtr(6 * 3600),
+		},
+		{
+			name:     "late",
+			lt:       liveTask{firstAt: t0, lastAt: at(8), progress: 0.5, spend: 40},
+			burnRate: 5, progRate: 0.0625, eta: ptr(at(16)), slackSecs: floatPtr(-6 * 3600),
+		},
+		{
+			name:     "no progress yet",
+			lt:       liveTask{firstAt: t0, lastAt: at(2), spend: 20},
+			burnRate: 10,
+		},
+		{
+			name: "single event",
+			lt:   liveTask{firstAt: t0, lastAt: t0, progress: 0.3, spend: 30},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			task := &types.TaskSpec{TaskID: "t1", MaxBudget: 100, Deadline: &deadline, StartedAt: tc.started}
+			now := tc.lt.lastAt.Add(time.Hour)
+			got := deriveLiveState(task, &tc.lt, now)
+			
+			if !approx(got.BudgetUsed, tc.lt.spend/100) {
+				t.Errorf("BudgetUsed = %v, want %v", got.BudgetUsed, tc.lt.spend/100)
+			}
+			if !approx(got.SinceLastEvent, 3600) {
+				t.Errorf("SinceLastEvent = %v, want 3600", got.SinceLastEvent)
+			}
+			if !approx(got.BurnRate, tc.burnRate) || !approx(got.ProgressRate, tc.progRate) {
+				t.Errorf("rates = %v/h spend, %v/h progress, want %v, %v", got.BurnRate, got.ProgressRate, tc.burnRate, tc.progRate)
+			}
+			switch {
+			case (got.ETA == nil) != (tc.eta == nil):
+				t.Errorf("ETA = %v, want %v", got.ETA, tc.eta)
+			case got.ETA != nil && got.ETA.Sub(*tc.eta).Abs() > time.Second:
+				t.Errorf("ETA = %v, want %v", *got.ETA, *tc.eta)
+			}
+			switch {
+			case (got.DeadlineSlack == nil) != (tc.slackSecs == nil):
+				t.Errorf("DeadlineSlack = %v, want %v", got.DeadlineSlack, tc.slackSecs)
+			case got.DeadlineSlack != nil && math.Abs(*got.DeadlineSlack-*tc.slackSecs) > 1:
+				t.Errorf("DeadlineSlack = %v, want %v", *got.DeadlineSlack, *tc.slackSecs)
+			}
+		})
+	}
+}
+
+func TestDeriveLiveStateProjectedSpend(t *testing.T) {
+	task := &types.TaskSpec{TaskID: "t1"}
+	tests := []struct {
+		progress, spend, want float64
+	}{
+		{0.25, 30, 120},
+		{1, 80, 80},
+		{0, 30, 0}, // No progress, no projection
+	}
+	for _, tc := range tests {
+		got := deriveLiveState(task, &liveTask{progress: tc.progress, spend: tc.spend}, time.Now())
+		if !approx(got.ProjectedSpend, tc.want) {
+			t.Errorf("ProjectedSpend at %v progress, %v spent = %v, want %v", tc.progress, tc.spend, got.ProjectedSpend, tc.want)
+		}
+		if got.BudgetUsed != 0 {
+			t.Errorf("BudgetUsed without a budget = %v, want 0", got.BudgetUsed)
+		}
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,197 @@
+package engine
+
+import (
+	"log"
+	"net/http"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+	nc "github.com/dataparency-dev/natsclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// METRICS (Sections 4.5, 4.9)
+// Prometheus counters and histograms for the delegation lifecycle and for the
+// natsclient calls underneath it. Tasks by status are read from the catalog,
+// and natsclient's retry counts from the client, at scrape time; everything
+// else is recorded as it happens. All recording
+// methods are no-ops on a nil *Metrics, so metrics stay optional.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+const metricsNamespace = "delegation"
+
+// Metrics holds the engine's Prometheus collectors.
+type Metrics struct {
+	engine *Engine
+	
+	tasksByStatus *prometheus.Desc
+	natsRetries   *prometheus.Desc
+	bidsPerTask   prometheus.Histogram
+	timeToAssign  prometheus.Histogram
+	verifications *prometheus.CounterVec
+	triggers      *prometheus.CounterVec
+	breakerTrips  *prometheus.CounterVec
+	natsLatency   *prometheus.HistogramVec
+	natsTimeouts  *prometheus.CounterVec
+}
+
+// EnableMetrics creates the engine's collectors, registers them with reg and
+// starts recording.
+func (e *Engine) EnableMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		engine: e,
+		tasksByStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "tasks"),
+			"Tasks in the catalog by status.",
+			[]string{"status"}, nil,
+		),
+		natsRetries: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "natsclient", "retries_total"),
+			"Times natsclient waited again for a server response, by operation (process-wide).",
+			[]string{"op"}, nil,
+		),
+		bidsPerTask: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "bids_per_task",
+			Help:      "Bids in a task's bid book when a bid is accepted.",
+			Buckets:   []float64{1, 2, 3, 5, 8, 13, 21},
+		}),
+		timeToAssign: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "time_to_assignment_seconds",
+			Help:      "Time from task creation to an accepted bid.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 10), // 1s to ~3 days
+		}),
+		verifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "verifications_total",
+			Help:      "Verification outcomes recorded, by result.",
+		}, []string{"result"}),
+		triggers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "triggers_total",
+			Help:      "Adaptive coordination triggers raised, by type.",
+		}, []string{"type"}),
+		breakerTrips: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "circuit_breaker_trips_total",
+			Help:      "Circuit breakers tripped, by reason.",
+		}, []string{"reason"}),
+		natsLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "natsclient",
+			Name:      "request_duration_seconds",
+			Help:      "natsclient call latency, by operation.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14), // 5ms to ~40s
+		}, []string{"op"}),
+		natsTimeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "natsclient",
+			Name:      "timeouts_total",
+			Help:      "natsclient calls that timed out, by operation.",
+		}, []string{"op"}),
+	}
+	
+	for _, c := range []prometheus.Collector{
+		m, m.bidsPerTask, m.timeToAssign, m.verifications, m.triggers,
+		m.breakerTrips, m.natsLatency, m.natsTimeouts,
+	} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	e.Metrics = m
+	return m, nil
+}
+
+// Describe implements prometheus.Collector for the task status gauge.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.tasksByStatus
+	ch <- m.natsRetries
+}
+
+// Collect implements prometheus.Collector, counting catalog tasks by status
+// and reading natsclient's retry counters.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, op := range []string{"get", "post"} {
+		ch <- prometheus.MustNewConstMetric(m.natsRetries, prometheus.CounterValue, float64(nc.RetryCount(op)), op)
+	}
+	
+	catalog, err := m.engine.loadTaskCatalog()
+	if err != nil {
+		log.Printf("Collect task metrics: %v", err)
+		return
+	}
+	counts := make(map[t.TaskStatus]int)
+	for _, entry := range catalog {
+		counts[entry.Status]++
+	}
+	for status, n := range counts {
+		ch <- prometheus.MustNewConstMetric(m.tasksByStatus, prometheus.GaugeValue, float64(n), string(status))
+	}
+}
+
+// BreakerTripped counts a circuit breaker trip. Assign it to a
+// security.CircuitBreaker's OnTrip hook.
+func (m *Metrics) BreakerTripped(agentID, reason string) {
+	if m == nil {
+		return
+	}
+	m.breakerTrips.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) observeAssignment(task *t.TaskSpec, bids int, at time.Time) {
+	if m == nil {
+		return
+	}
+	m.bidsPerTask.Observe(float64(bids))
+	if !task.CreatedAt.IsZero() {
+		m.timeToAssign.Observe(at.Sub(task.CreatedAt).Seconds())
+	}
+}
+
+func (m *Metrics) observeVerification(passed bool) {
+	if m == nil {
+		return
+	}
+	result := "failed"
+	if passed {
+		result = "passed"
+	}
+	m.verifications.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) observeTrigger(kind t.TriggerType) {
+	if m == nil {
+		return
+	}
+	m.triggers.WithLabelValues(string(kind)).Inc()
+}
+
+// observeNATS records one natsclient call. Get and Post report timeouts as
+// HTTP 408/504 statuses; channel calls report them through timedOut.
+func (m *Metrics) observeNATS(op string, start time.Time, status int, timedOut bool) {
+	if m == nil {
+		return
+	}
+	m.natsLatency.WithLabelValues(op).Observe(time.Since(start).Seconds())
+	if timedOut || status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout {
+		m.natsTimeouts.WithLabelValues(op).Inc()
+	}
+}
+
+// ServeMetrics exposes a gatherer's metrics at /metrics on addr in the
+// background. Shut the returned server down to stop it.
+func ServeMetrics(addr string, gatherer prometheus.Gatherer) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("Metrics endpoint on %s: %v", addr, err)
+		}
+	}()
+	return srv
+}
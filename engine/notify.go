@@ -0,0 +1,375 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+	
+	"github.com/dataparency-dev/AI-delegation/security"
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// HUMAN OVERSIGHT NOTIFICATIONS (Sections 4.4, 4.9)
+// Escalations, security alerts and triggers are routed to the agents that
+// oversee delegation — human overseers above all. Routes pick recipients by
+// role and type and filter on severity and task criticality; named Notifiers
+// (webhook, SMTP, file, stdout in the notify package) carry the message out
+// in the background; a route without roles only goes to its notifiers. Every
+// routed notification also lands in each recipient's inbox, one shard per
+// delivering engine. Anyone can register claiming the overseer role, so overseers only
+// receive notifications once the router lists them in Overseers.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// Notifier delivers a routed notification outside the engine.
+type Notifier interface {
+	Notify(n t.Notification) error
+}
+
+// NotificationRouter holds the routes and the named notifiers they deliver through.
+type NotificationRouter struct {
+	Routes    []t.NotificationRoute
+	Overseers []string // Agents trusted with the overseer role; other self-declared overseers get nothing
+	notifiers map[string]Notifier
+	pending   sync.WaitGroup // Deliveries still running
+}
+
+// NewNotificationRouter returns a router for routes, or for
+// DefaultNotificationRoutes when none are given.
+func NewNotificationRouter(routes ...t.NotificationRoute) *NotificationRouter {
+	if len(routes) == 0 {
+		routes = DefaultNotificationRoutes()
+	}
+	return &NotificationRouter{Routes: routes, notifiers: make(map[string]Notifier)}
+}
+
+// DefaultNotificationRoutes sends every escalation and security alert to the
+// registered overseers, and urgent triggers on high-criticality tasks to human
+// overseers.
+func DefaultNotificationRoutes() []t.NotificationRoute {
+	return []t.NotificationRoute{
+		{
+			Name:  "overseers",
+			Kinds: []t.NotificationKind{t.NotifyEscalation, t.NotifySecurityAlert},
+			Roles: []t.AgentRole{t.RoleOverseer},
+		},
+		{
+			Name:           "urgent-triggers",
+			Kinds:          []t.NotificationKind{t.NotifyTrigger},
+			MinSeverity:    t.CriticalityHigh,
+			MinCriticality: t.CriticalityHigh,
+			Roles:          []t.AgentRole{t.RoleOverseer},
+			AgentTypes:     []t.AgentType{t.AgentTypeHuman},
+		},
+	}
+}
+
+// Register adds a notifier under name, replacing any with the same name.
+func (r *NotificationRouter) Register(name string, n Notifier) {
+	r.notifiers[name] = n
+}
+
+// Wait blocks until every delivery already started has finished, e.g. before exit.
+func (r *NotificationRouter) Wait() {
+	r.pending.Wait()
+}
+
+// trustedOverseer reports whether agentID is on the overseer allow-list.
+func (r *NotificationRouter) trustedOverseer(agentID string) bool {
+	for _, id := range r.Overseers {
+		if id == agentID {
+			return true
+		}
+	}
+	return false
+}
+
+// deliverers returns the notifiers a route names, or every registered one.
+func (r *NotificationRouter) deliverers(route t.NotificationRoute) map[string]Notifier {
+	if len(route.Notifiers) == 0 {
+		return r.notifiers
+	}
+	out := make(map[string]Notifier, len(route.Notifiers))
+	for _, name := range route.Notifiers {
+		if n, ok := r.notifiers[name]; ok {
+			out[name] = n
+		} else {
+			log.Printf("Notification route %s: no notifier named %q", route.Name, name)
+		}
+	}
+	return out
+}
+
+// notify routes n to its recipients. Notifiers run in the background and their
+// failures are logged, not returned, so a slow or broken mail server never
+// blocks an escalation.
+func (e *Engine) notify(n t.Notification) {
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	if n.NotificationID == "" {
+		n.NotificationID = fmt.Sprintf("notify_%s_%s_%d", n.Kind, n.TaskID, n.CreatedAt.UnixNano())
+	}
+	log.Printf("NOTIFY [%s/%s] %s: %s", n.Kind, n.Severity, n.Title, n.Body)
+	
+	if e.Notifications == nil {
+		return
+	}
+	for _, route := range e.Notifications.Routes {
+		if !route.Matches(n) {
+			continue
+		}
+		recipients, err := e.routeRecipients(route)
+		if err != nil {
+			log.Printf("Notification route %s: %v", route.Name, err)
+			continue
+		}
+		if len(recipients) == 0 && len(route.Roles) > 0 {
+			continue // Nobody holds the roles the route is for
+		}
+		routed := n
+		routed.Route = route.Name
+		routed.Recipients = recipients
+		
+		for _, r := range recipients {
+			if err := e.appendSharedList(DomainAgents, r.AgentID, "inbox", routed); err != nil {
+				log.Printf("Deliver %s to %s inbox: %v", n.NotificationID, r.AgentID, err)
+			}
+		}
+		for name, notifier := range e.Notifications.deliverers(route) {
+			e.Notifications.pending.Add(1)
+			go func(name string, notifier Notifier) {
+				defer e.Notifications.pending.Done()
+				if err := notifier.Notify(routed); err != nil {
+					log.Printf("Notifier %s failed for %s: %v", name, routed.NotificationID, err)
+				}
+			}(name, notifier)
+		}
+	}
+}
+
+// routeRecipients resolves a route's roles to registered agents, narrowed by
+// agent type. Overseers must be on the router's allow-list. Offline agents
+// still receive notifications in their inbox.
+func (e *Engine) routeRecipients(route t.NotificationRoute) ([]t.Recipient, error) {
+	var out []t.Recipient
+	seen := make(map[string]bool)
+	for _, role := range route.Roles {
+		agents, err := e.FindAgentsByRole(role)
+		if err != nil {
+			return nil, err
+		}
+		for _, agent := range agents {
+			if seen[agent.AgentID] || !typeAllowed(route.AgentTypes, agent.Type) {
+				continue
+			}
+			if role == t.RoleOverseer && !e.Notifications.trustedOverseer(agent.AgentID) {
+				log.Printf("Notification route %s: %s claims the overseer role but is not trusted", route.Name, agent.AgentID)
+				continue
+			}
+			seen[agent.AgentID] = true
+			out = append(out, t.Recipient{
+				AgentID: agent.AgentID,
+				Name:    agent.Name,
+				Type:    agent.Type,
+				Role:    agent.Role,
+				Email:   agent.Metadata["email"],
+				Webhook: agent.Metadata["webhook"],
+			})
+		}
+	}
+	return out, nil
+}
+
+func typeAllowed(types []t.AgentType, at t.AgentType) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, allowed := range types {
+		if allowed == at {
+			return true
+		}
+	}
+	return false
+}
+
+// GetInbox returns the notifications routed to an agent by every engine,
+// oldest first. An empty inbox is not an error; read failures are.
+func (e *Engine) GetInbox(agentID string) ([]t.Notification, error) {
+	raw, err := e.readSharedList(DomainAgents, agentID, "inbox")
+	if err != nil {
+		return nil, err
+	}
+	inbox := make([]t.Notification, len(raw))
+	for i, item := range raw {
+		if err := json.Unmarshal(item, &inbox[i]); err != nil {
+			return nil, fmt.Errorf("unmarshal inbox for %s: %w", agentID, err)
+		}
+	}
+	sort.SliceStable(inbox, func(i, j int) bool {
+		return inbox[i].CreatedAt.Before(inbox[j].CreatedAt)
+	})
+	return inbox, nil
+}
+
+// ─── Role Index ──────────────────────────────────────────────────────────────
+
+// indexAgentRole records the agent under its role in this engine's shard of
+// the role index, so overseers can be found without a capability query.
+func (e *Engine) indexAgentRole(profile t.AgentProfile) error {
+	e.indexMu.Lock()
+	defer e.indexMu.Unlock()
+	
+	if err := e.registerWriter(DomainAgents, "index", "roles"); err != nil {
+		return err
+	}
+	roles := make(map[t.AgentRole][]string)
+	if _, err := e.retrieveJSON(DomainAgents, "index", e.shardAspect("roles"), &roles); err != nil {
+		return err
+	}
+	for _, id := range roles[profile.Role] {
+		if id == profile.AgentID {
+			return nil
+		}
+	}
+	roles[profile.Role] = append(roles[profile.Role], profile.AgentID)
+	body, err := json.Marshal(roles)
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainAgents, "index", e.shardAspect("roles"), body)
+}
+
+// loadRoleIndex merges every engine's shard of the role index; an index nobody
+// has written yet is empty. Read failures are returned.
+func (e *Engine) loadRoleIndex() (map[t.AgentRole][]string, error) {
+	writers, err := e.shardWriters(DomainAgents, "index", "roles")
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[t.AgentRole][]string)
+	seen := make(map[string]bool)
+	for _, w := range writers {
+		shard := make(map[t.AgentRole][]string)
+		if _, err := e.retrieveJSON(DomainAgents, "index", "roles_"+w, &shard); err != nil {
+			return nil, fmt.Errorf("role index shard %s: %w", w, err)
+		}
+		for role, ids := range shard {
+			for _, id := range ids {
+				if key := string(role) + "|" + id; !seen[key] {
+					seen[key] = true
+					roles[role] = append(roles[role], id)
+				}
+			}
+		}
+	}
+	return roles, nil
+}
+
+// FindAgentsByRole returns the registered agents that currently hold role,
+// ordered by agent ID.
+func (e *Engine) FindAgentsByRole(role t.AgentRole) ([]t.AgentProfile, error) {
+	index, err := e.loadRoleIndex()
+	if err != nil {
+		return nil, fmt.Errorf("role index: %w", err)
+	}
+	ids := index[role]
+	
+	sort.Strings(ids)
+	var agents []t.AgentProfile
+	for _, id := range ids {
+		agent, err := e.GetAgent(id)
+		if isNotFound(err) {
+			continue // Removed since it was indexed
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get agent %s: %w", id, err)
+		}
+		if agent.Role == role {
+			agents = append(agents, *agent)
+		}
+	}
+	return agents, nil
+}
+
+// ─── Security Alerts ─────────────────────────────────────────────────────────
+
+// RaiseSecurityAlert records a detected threat, notifies the overseers and,
+// when the alert concerns a task, raises a security_alert trigger on it.
+// High and critical alerts make the trigger urgent.
+func (e *Engine) RaiseSecurityAlert(alert security.SecurityAlert) error {
+	if alert.Timestamp.IsZero() {
+		alert.Timestamp = time.Now()
+	}
+	if alert.AlertID == "" {
+		alert.AlertID = fmt.Sprintf("alert_%s_%s_%d", alert.ThreatType, alert.AgentID, alert.Timestamp.UnixNano())
+	}
+	
+	n := t.Notification{
+		Kind:     t.NotifySecurityAlert,
+		Severity: alert.Severity,
+		TaskID:   alert.TaskID,
+		AgentID:  alert.AgentID,
+		Title:    fmt.Sprintf("Security alert: %s", alert.ThreatType),
+		Body:     alert.Description,
+	}
+	if alert.Evidence != "" {
+		n.Body += "\nEvidence: " + alert.Evidence
+	}
+	
+	if alert.TaskID == "" {
+		if err := e.appendSharedList(DomainAgents, alert.AgentID, "security_alerts", alert); err != nil {
+			return fmt.Errorf("store security alert %s: %w", alert.AlertID, err)
+		}
+		e.notify(n)
+		return nil
+	}
+	
+	if err := e.appendSharedList(DomainTriggers, alert.TaskID, "security_alerts", alert); err != nil {
+		return fmt.Errorf("store security alert %s: %w", alert.AlertID, err)
+	}
+	if task, err := e.GetTask(alert.TaskID); err == nil {
+		n.TaskCriticality = task.Criticality
+	}
+	e.notify(n)
+	
+	return e.RaiseTrigger(t.AdaptiveTrigger{
+		TriggerID:   alert.AlertID,
+		TaskID:      alert.TaskID,
+		Type:        t.TriggerExtSecurityAlert,
+		AgentID:     alert.AgentID,
+		Description: fmt.Sprintf("%s: %s", alert.ThreatType, alert.Description),
+		Urgent:      alert.Severity.Rank() >= t.CriticalityHigh.Rank(),
+	})
+}
+
+// GetSecurityAlerts returns the alerts raised against a task by every engine,
+// oldest first. Read failures are returned.
+func (e *Engine) GetSecurityAlerts(taskID string) ([]security.SecurityAlert, error) {
+	raw, err := e.readSharedList(DomainTriggers, taskID, "security_alerts")
+	if err != nil {
+		return nil, err
+	}
+	alerts := make([]security.SecurityAlert, len(raw))
+	for i, item := range raw {
+		if err := json.Unmarshal(item, &alerts[i]); err != nil {
+			return nil, fmt.Errorf("unmarshal security alerts for %s: %w", taskID, err)
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].Timestamp.Before(alerts[j].Timestamp)
+	})
+	return alerts, nil
+}
+
+// escalationSeverity is the task's criticality, but never below high: an
+// escalation always needs a person to look at it.
+func escalationSeverity(task *t.TaskSpec) t.Criticality {
+	if task.Criticality.Rank() > t.CriticalityHigh.Rank() {
+		return task.Criticality
+	}
+	return t.CriticalityHigh
+}
@@ -0,0 +1,502 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultExtendBy is the budget fraction an extend rule proposes when it sets none.
+const DefaultExtendBy = 0.2
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// ADAPTIVE RESPONSE POLICIES (Section 4.4)
+// evaluateAndRespond asks a ResponsePolicy what to do about a trigger. Rules
+// look at the trigger, the task, its contract terms and the delegatee's trust;
+// the first match picks the action. Policies are published as new versions:
+//   Policies/{name}/v{n}              → ResponsePolicy snapshot
+//   Policies/{name}/current           → Latest ResponsePolicy
+//   Triggers/{task}/decision_{trigger} → PolicyDecision taken for a trigger
+// DryRunPolicy evaluates a policy against a trigger without acting on it.
+// ActivateResponsePolicy picks up the current published version at startup.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// DefaultResponsePolicy is the built-in decision tree from Figure 2, used when
+// the engine has no policy set.
+func DefaultResponsePolicy() t.ResponsePolicy {
+	yes, no := true, false
+	return t.ResponsePolicy{
+		Name:        "default",
+		Version:     1,
+		Description: "Reversibility, then urgency, then trigger scope",
+		Rules: []t.ResponseRule{
+			{
+				Name:   "priority-shift",
+				When:   t.RuleCondition{TriggerTypes: []t.TriggerType{t.TriggerExtPriorityShift}},
+				Action: t.ActionReprioritize,
+				Reason: "priority shifts only reorder work",
+			},
+			{
+				Name:   "halt-irreversible",
+				When:   t.RuleCondition{Reversible: &no, Urgent: &yes},
+				Action: t.ActionCancel,
+				Notify: true,
+				Reason: "urgent trigger on irreversible task",
+			},
+			{
+				Name:   "urgent-redelegate",
+				When:   t.RuleCondition{Urgent: &yes},
+				Action: t.ActionReDelegate,
+				Reason: "urgent trigger",
+			},
+			{
+				Name:   "budget-no-contract",
+				When:   t.RuleCondition{TriggerTypes: []t.TriggerType{t.TriggerIntBudgetOverrun}, HasContract: &no},
+				Action: t.ActionEscalate,
+				Reason: "budget overrun on a task with no active contract",
+			},
+			{
+				Name:   "budget-exhausted",
+				When:   t.RuleCondition{TriggerTypes: []t.TriggerType{t.TriggerIntBudgetOverrun}, ExtensionsExhausted: &yes},
+				Action: t.ActionEscalate,
+				Reason: "budget overrun after the contract's extensions ran out",
+			},
+			{
+				Name:     "budget-extend",
+				When:     t.RuleCondition{TriggerTypes: []t.TriggerType{t.TriggerIntBudgetOverrun}},
+				Action:   t.ActionExtend,
+				ExtendBy: DefaultExtendBy,
+				Reason:   "budget overrun",
+			},
+			{
+				Name:   "failing-agent",
+				When:   t.RuleCondition{TriggerTypes: []t.TriggerType{t.TriggerIntPerfDrop, t.TriggerIntUnresponsive}},
+				Action: t.ActionReDelegate,
+				Reason: "delegatee is failing",
+			},
+			{
+				Name:   "verification-failed",
+				When:   t.RuleCondition{TriggerTypes: []t.TriggerType{t.TriggerIntVerifyFail}},
+				Action: t.ActionReExecute,
+				Reason: "result failed verification",
+			},
+		},
+	}
+}
+
+// LoadResponsePolicy reads a policy from a JSON or YAML file. YAML keys are the
+// JSON field names, and a key the policy does not have is an error rather
+// than a silently ignored typo. The version is left for PublishResponsePolicy
+// to assign.
+func LoadResponsePolicy(path string) (*t.ResponsePolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parse policy file %s: %w", path, err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("policy file %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("policy file %s: unsupported extension", path)
+	}
+	
+	var policy t.ResponsePolicy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("parse policy file %s: %w", path, err)
+	}
+	if err := ValidateResponsePolicy(policy); err != nil {
+		return nil, fmt.Errorf("policy file %s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// ValidateResponsePolicy rejects policies with unnamed rules, unknown actions,
+// trigger types or criticality levels, or an empty criticality range.
+func ValidateResponsePolicy(policy t.ResponsePolicy) error {
+	if policy.Name == "" {
+		return fmt.Errorf("policy has no name")
+	}
+	seen := make(map[string]bool)
+	for i, rule := range policy.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d has no name", i+1)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("duplicate rule %q", rule.Name)
+		}
+		seen[rule.Name] = true
+		switch rule.Action {
+		case t.ActionExtend, t.ActionReDelegate, t.ActionEscalate, t.ActionPause,
+			t.ActionCheckpoint, t.ActionCancel, t.ActionReExecute, t.ActionReprioritize, t.ActionMonitor:
+		default:
+			return fmt.Errorf("rule %q: unknown action %q", rule.Name, rule.Action)
+		}
+		if rule.ExtendBy < 0 {
+			return fmt.Errorf("rule %q: extend_by must not be negative", rule.Name)
+		}
+		for _, tt := range rule.When.TriggerTypes {
+			if !knownTriggerType(tt) {
+				return fmt.Errorf("rule %q: unknown trigger type %q", rule.Name, tt)
+			}
+		}
+		for _, c := range []t.Criticality{rule.When.MinCriticality, rule.When.MaxCriticality} {
+			if c != "" && !knownCriticality(c) {
+				return fmt.Errorf("rule %q: unknown criticality %q", rule.Name, c)
+			}
+		}
+		if min, max := rule.When.MinCriticality, rule.When.MaxCriticality; min != "" && max != "" && min.Rank() > max.Rank() {
+			return fmt.Errorf("rule %q: min_criticality %s is above max_criticality %s", rule.Name, min, max)
+		}
+	}
+	return nil
+}
+
+func knownTriggerType(tt t.TriggerType) bool {
+	switch tt {
+	case t.TriggerExtTaskChange, t.TriggerExtResourceShift, t.TriggerExtPriorityShift, t.TriggerExtSecurityAlert,
+		t.TriggerIntPerfDrop, t.TriggerIntBudgetOverrun, t.TriggerIntVerifyFail, t.TriggerIntUnresponsive,
+		t.TriggerIntMonitorBreach:
+		return true
+	}
+	return false
+}
+
+func knownCriticality(c t.Criticality) bool {
+	switch c {
+	case t.CriticalityLow, t.CriticalityMedium, t.CriticalityHigh, t.CriticalityCritical:
+		return true
+	}
+	return false
+}
+
+// PublishResponsePolicy stores policy as the next version of its name and makes
+// it the engine's active policy. Older versions stay readable for audit and
+// roll-back (publish an old version again to restore it).
+//
+// The store has no conditional writes, so another engine may be publishing the
+// same name: a version slot that is already taken is skipped, and the write is
+// read back to confirm no other engine claimed the slot at the same moment.
+func (e *Engine) PublishResponsePolicy(policy t.ResponsePolicy) (*t.ResponsePolicy, error) {
+	if err := ValidateResponsePolicy(policy); err != nil {
+		return nil, err
+	}
+	
+	e.policyMu.Lock()
+	defer e.policyMu.Unlock()
+	
+	// Only a policy that was never published starts over at version 1
+	var current t.ResponsePolicy
+	found, err := e.retrieveJSON(DomainPolicies, policy.Name, "current", &current)
+	if err != nil {
+		return nil, fmt.Errorf("read current policy %s: %w", policy.Name, err)
+	}
+	policy.Version = 1
+	if found {
+		policy.Version = current.Version + 1
+	}
+	
+	var body []byte
+	for attempt := 0; ; attempt++ {
+		if attempt == 3 {
+			return nil, fmt.Errorf("publish policy %s: version v%d kept being claimed by other engines", policy.Name, policy.Version)
+		}
+		if policy.Version, err = e.freePolicyVersion(policy.Name, policy.Version); err != nil {
+			return nil, err
+		}
+		policy.PublishedAt = time.Now()
+		if body, err = json.Marshal(policy); err != nil {
+			return nil, err
+		}
+		slot := "v" + strconv.Itoa(policy.Version)
+		if err := e.storeData(DomainPolicies, policy.Name, slot, body); err != nil {
+			return nil, fmt.Errorf("store policy %s v%d: %w", policy.Name, policy.Version, err)
+		}
+		var stored t.ResponsePolicy
+		if _, err := e.retrieveJSON(DomainPolicies, policy.Name, slot, &stored); err != nil {
+			return nil, fmt.Errorf("confirm policy %s v%d: %w", policy.Name, policy.Version, err)
+		}
+		if stored.PublishedAt.Equal(policy.PublishedAt) {
+			break
+		}
+		policy.Version++
+	}
+	if err := e.storeData(DomainPolicies, policy.Name, "current", body); err != nil {
+		return nil, fmt.Errorf("store current policy %s: %w", policy.Name, err)
+	}
+	
+	e.policy = &policy
+	log.Printf("Response policy %s v%d active (%d rules)", policy.Name, policy.Version, len(policy.Rules))
+	return &policy, nil
+}
+
+// freePolicyVersion returns the first version of name from version on whose
+// slot has not been written.
+func (e *Engine) freePolicyVersion(name string, version int) (int, error) {
+	for ; ; version++ {
+		_, err := e.retrieveData(DomainPolicies, name, "v"+strconv.Itoa(version))
+		if isNotFound(err) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("read policy %s v%d: %w", name, version, err)
+		}
+	}
+}
+
+// ActivateResponsePolicy makes the current published version of name the
+// engine's active policy, e.g. when an engine restarts. The error wraps
+// ErrNotFound if name was never published.
+func (e *Engine) ActivateResponsePolicy(name string) (*t.ResponsePolicy, error) {
+	e.policyMu.Lock()
+	defer e.policyMu.Unlock()
+	
+	var policy t.ResponsePolicy
+	found, err := e.retrieveJSON(DomainPolicies, name, "current", &policy)
+	if err != nil {
+		return nil, fmt.Errorf("read current policy %s: %w", name, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("policy %s: %w", name, ErrNotFound)
+	}
+	e.policy = &policy
+	log.Printf("Response policy %s v%d active (%d rules)", policy.Name, policy.Version, len(policy.Rules))
+	return &policy, nil
+}
+
+// GetResponsePolicy retrieves a published policy version; version 0 is the latest.
+func (e *Engine) GetResponsePolicy(name string, version int) (*t.ResponsePolicy, error) {
+	aspect := "current"
+	if version > 0 {
+		aspect = "v" + strconv.Itoa(version)
+	}
+	data, err := e.retrieveData(DomainPolicies, name, aspect)
+	if err != nil {
+		return nil, err
+	}
+	var policy t.ResponsePolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("unmarshal policy %s: %w", name, err)
+	}
+	return &policy, nil
+}
+
+// activePolicy returns the engine's policy, or the built-in default.
+func (e *Engine) activePolicy() t.ResponsePolicy {
+	e.policyMu.RLock()
+	defer e.policyMu.RUnlock()
+	if e.policy != nil {
+		return *e.policy
+	}
+	return DefaultResponsePolicy()
+}
+
+// DryRunPolicy reports what policy would do about trigger without acting on
+// it or storing the decision. A nil policy means the engine's active one.
+func (e *Engine) DryRunPolicy(policy *t.ResponsePolicy, trigger t.AdaptiveTrigger) (*t.PolicyDecision, error) {
+	if policy == nil {
+		active := e.activePolicy()
+		policy = &active
+	}
+	task, err := e.GetTask(trigger.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	decision := e.decide(*policy, task, trigger)
+	decision.DryRun = true
+	return decision, nil
+}
+
+// decide evaluates policy against a trigger on task.
+func (e *Engine) decide(policy t.ResponsePolicy, task *t.TaskSpec, trigger t.AdaptiveTrigger) *t.PolicyDecision {
+	facts := e.gatherFacts(task)
+	decision := &t.PolicyDecision{
+		TriggerID:     trigger.TriggerID,
+		TaskID:        task.TaskID,
+		PolicyName:    policy.Name,
+		PolicyVersion: policy.Version,
+		Action:        t.ActionMonitor,
+		Reason:        "no rule matched",
+		DecidedAt:     time.Now(),
+	}
+	for _, rule := range policy.Rules {
+		if !ruleMatches(rule.When, task, trigger, facts) {
+			continue
+		}
+		decision.Rule = rule.Name
+		decision.Action = rule.Action
+		decision.Notify = rule.Notify
+		decision.Reason = rule.Reason
+		if rule.Action == t.ActionExtend {
+			decision.ExtendBy = rule.ExtendBy
+			if decision.ExtendBy == 0 {
+				decision.ExtendBy = DefaultExtendBy
+			}
+		}
+		break
+	}
+	return decision
+}
+
+// policyFacts is what rules can see beyond the trigger and task.
+type policyFacts struct {
+	contract *t.DelegationContract // Nil when the task has none or it cannot be read
+	trust    *float64              // Delegatee trust score; nil when unassigned
+}
+
+func (e *Engine) gatherFacts(task *t.TaskSpec) policyFacts {
+	var facts policyFacts
+	if task.ContractID != "" {
+		if contract, err := e.GetContract(task.ContractID); err == nil {
+			facts.contract = contract
+		}
+	}
+	if task.DelegateeID != "" {
+		if agent, err := e.GetAgent(task.DelegateeID); err == nil {
+			trust := agent.TrustScore
+			facts.trust = &trust
+		}
+	}
+	return facts
+}
+
+func ruleMatches(c t.RuleCondition, task *t.TaskSpec, trigger t.AdaptiveTrigger, facts policyFacts) bool {
+	if len(c.TriggerTypes) > 0 {
+		found := false
+		for _, tt := range c.TriggerTypes {
+			if tt == trigger.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if c.Urgent != nil && *c.Urgent != trigger.Urgent {
+		return false
+	}
+	if c.Reversible != nil && *c.Reversible != task.Reversible {
+		return false
+	}
+	if c.MinCriticality != "" && task.Criticality.Rank() < c.MinCriticality.Rank() {
+		return false
+	}
+	if c.MaxCriticality != "" && task.Criticality.Rank() > c.MaxCriticality.Rank() {
+		return false
+	}
+	
+	if c.HasContract != nil && *c.HasContract != (facts.contract != nil) {
+		return false
+	}
+	if c.ExtensionsExhausted != nil {
+		exhausted := facts.contract != nil && extensionsExhausted(facts.contract)
+		if *c.ExtensionsExhausted != exhausted {
+			return false
+		}
+	}
+	if c.MinMaxCost != nil || c.MaxMaxCost != nil {
+		if facts.contract == nil {
+			return false
+		}
+		cost := facts.contract.Terms.MaxCost
+		if (c.MinMaxCost != nil && cost < *c.MinMaxCost) || (c.MaxMaxCost != nil && cost > *c.MaxMaxCost) {
+			return false
+		}
+	}
+	
+	if c.MinTrust != nil || c.MaxTrust != nil {
+		if facts.trust == nil {
+			return false
+		}
+		if (c.MinTrust != nil && *facts.trust < *c.MinTrust) || (c.MaxTrust != nil && *facts.trust > *c.MaxTrust) {
+			return false
+		}
+	}
+	return true
+}
+
+// respond carries out a policy decision for a trigger on task.
+func (e *Engine) respond(task *t.TaskSpec, trigger t.AdaptiveTrigger, decision *t.PolicyDecision) error {
+	reason := decision.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("%s trigger", trigger.Type)
+	}
+	if decision.Notify {
+		e.notify(t.Notification{
+			Kind:            t.NotifyEscalation,
+			Severity:        escalationSeverity(task),
+			TaskID:          task.TaskID,
+			TaskCriticality: task.Criticality,
+			AgentID:         task.DelegateeID,
+			Title:           fmt.Sprintf("Task %s: %s (rule %s)", task.TaskID, decision.Action, decision.Rule),
+			Body:            fmt.Sprintf("%s — %s trigger: %s", reason, trigger.Type, trigger.Description),
+		})
+	}
+	
+	switch decision.Action {
+	case t.ActionReprioritize:
+		return e.applyPriorityShift(task, trigger)
+	case t.ActionCancel:
+		_, err := e.CancelTask(task.TaskID, fmt.Sprintf("%s (%s trigger)", reason, trigger.Type))
+		return err
+	case t.ActionReDelegate:
+		return e.reDelegate(task)
+	case t.ActionEscalate:
+		return e.escalate(task, reason)
+	case t.ActionExtend:
+		log.Printf("Budget overrun on task %s — evaluating extension", task.TaskID)
+		return e.proposeBudgetExtension(task, trigger, decision.ExtendBy)
+	case t.ActionReExecute:
+		task.Status = t.TaskReAllocating
+		return e.UpdateTask(*task)
+	case t.ActionCheckpoint:
+		_, err := e.RequestCheckpoint(task.TaskID, reason)
+		return err
+	case t.ActionPause:
+		return e.PauseTask(task.TaskID, reason)
+	default:
+		log.Printf("Non-urgent trigger %s on task %s — monitoring", trigger.Type, task.TaskID)
+		return nil
+	}
+}
+
+// storeDecision keeps the decision taken for a trigger next to the trigger.
+func (e *Engine) storeDecision(decision *t.PolicyDecision) error {
+	body, err := json.Marshal(decision)
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainTriggers, decision.TaskID, "decision_"+decision.TriggerID, body)
+}
+
+// GetPolicyDecision retrieves the decision taken for a trigger.
+func (e *Engine) GetPolicyDecision(taskID, triggerID string) (*t.PolicyDecision, error) {
+	data, err := e.retrieveData(DomainTriggers, taskID, "decision_"+triggerID)
+	if err != nil {
+		return nil, err
+	}
+	var decision t.PolicyDecision
+	if err := json.Unmarshal(data, &decision); err != nil {
+		return nil, fmt.Errorf("unmarshal policy decision: %w", err)
+	}
+	return &decision, nil
+}
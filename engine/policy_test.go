@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"testing"
+	
+	"github.com/dataparency-dev/AI-delegation/types"
+)
+
+func TestRuleMatches(t *testing.T) {
+	yes, no := true, false
+	num := func(v float64) *float64 { return &v }
+	
+	task := &types.TaskSpec{TaskID: "t1", Criticality: types.CriticalityHigh, Reversible: false}
+	trigger := types.AdaptiveTrigger{TaskID: "t1", Type: types.TriggerIntBudgetOverrun, Urgent: true}
+	contract := &types.DelegationContract{Terms: types.ContractTerms{MaxCost: 100, MaxExtensions: 2}, Extensions: 1}
+	exhausted := &types.DelegationContract{Terms: types.ContractTerms{MaxCost: 100, MaxExtensions: 2}, Extensions: 2}
+	withContract := policyFacts{contract: contract, trust: num(0.8)}
+	
+	tests := []struct {
+		name  string
+		when  types.RuleCondition
+		facts policyFacts
+		want  bool
+	}{
+		{"empty condition", types.RuleCondition{}, policyFacts{}, true},
+		{"trigger type listed", types.RuleCondition{TriggerTypes: []types.TriggerType{types.TriggerIntPerfDrop, types.TriggerIntBudgetOverrun}}, policyFacts{}, true},
+		{"trigger type not listed", types.RuleCondition{TriggerTypes: []types.TriggerType{types.TriggerIntPerfDrop}}, policyFacts{}, false},
+		{"urgent", types.RuleCondition{Urgent: &yes}, policyFacts{}, true},
+		{"not urgent", types.RuleCondition{Urgent: &no}, policyFacts{}, false},
+		{"irreversible", types.RuleCondition{Reversible: &no}, policyFacts{}, true},
+		{"reversible", types.RuleCondition{Reversible: &yes}, policyFacts{}, false},
+		{"criticality in range", types.RuleCondition{MinCriticality: types.CriticalityMedium, MaxCriticality: types.CriticalityHigh}, policyFacts{}, true},
+		{"criticality below min", types.RuleCondition{MinCriticality: types.CriticalityCritical}, policyFacts{}, false},
+		{"criticality above max", types.RuleCondition{MaxCriticality: types.CriticalityMedium}, policyFacts{}, false},
+		{"has contract", types.RuleCondition{HasContract: &yes}, withContract, true},
+		{"has no contract", types.RuleCondition{HasContract: &no}, policyFacts{}, true},
+		{"contract required but missing", types.RuleCondition{HasContract: &yes}, policyFacts{}, false},
+		{"extensions left", types.RuleCondition{ExtensionsExhausted: &no}, withContract, true},
+		{"extensions exhausted", types.RuleCondition{ExtensionsExhausted: &yes}, policyFacts{contract: exhausted}, true},
+		{"no contract is not exhausted", types.RuleCondition{ExtensionsExhausted: &yes}, policyFacts{}, false},
+		{"cost in range", types.RuleCondition{MinMaxCost: num(50), MaxMaxCost: num(100)}, withContract, true},
+		{"cost below min", types.RuleCondition{MinMaxCost: num(150)}, withContract, false},
+		{"cost above max", types.RuleCondition{MaxMaxCost: num(50)}, withContract, false},
+		{"cost bound without a contract", types.RuleCondition{MaxMaxCost: num(500)}, policyFacts{}, false},
+		{"trust in range", types.RuleCondition{MinTrust: num(0.5), MaxTrust: num(0.9)}, withContract, true},
+		{"trust below min", types.RuleCondition{MinTrust: num(0.9)}, withContract, false},
+		{"trust above max", types.RuleCondition{MaxTrust: num(0.5)}, withContract, false},
+		{"trust bound without a delegatee", types.RuleCondition{MinTrust: num(0)}, policyFacts{}, false},
+		{"every clause must hold", types.RuleCondition{Urgent: &yes, HasContract: &yes, MinTrust: num(0.9)}, withContract, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ruleMatches(tc.when, task, trigger, tc.facts); got != tc.want {
+				t.Errorf("ruleMatches() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateResponsePolicy(t *testing.T) {
+	rule := func(edit func(*types.ResponseRule)) types.ResponsePolicy {
+		r := types.ResponseRule{Name: "r1", Action: types.ActionEscalate}
+		edit(&r)
+		return types.ResponsePolicy{Name: "p", Rules: []types.ResponseRule{r}}
+	}
+	tests := []struct {
+		name    string
+		policy  types.ResponsePolicy
+		wantErr bool
+	}{
+		{"default policy", DefaultResponsePolicy(), false},
+		{"no name", types.ResponsePolicy{}, true},
+		{"unnamed rule", rule(func(r *types.ResponseRule) { r.Name = "" }), true},
+		{"duplicate rule", types.ResponsePolicy{Name: "p", Rules: []types.ResponseRule{
+			{Name: "r1", Action: types.ActionEscalate}, {Name: "r1", Action: types.ActionCancel},
+		}}, true},
+		{"unknown action", rule(func(r *types.ResponseRule) { r.Action = "shrug" }), true},
+		{"negative extend_by", rule(func(r *types.ResponseRule) { r.ExtendBy = -0.1 }), true},
+		{"unknown trigger type", rule(func(r *types.ResponseRule) {
+			r.When.TriggerTypes = []types.TriggerType{types.TriggerIntBudgetOverrun, "budget_overun"}
+		}), true},
+		{"unknown criticality", rule(func(r *types.ResponseRule) { r.When.MinCriticality = "severe" }), true},
+		{"criticality range", rule(func(r *types.ResponseRule) {
+			r.When.MinCriticality = types.CriticalityMedium
+			r.When.MaxCriticality = types.CriticalityHigh
+		}), false},
+		{"empty criticality range", rule(func(r *types.ResponseRule) {
+			r.When.MinCriticality = types.CriticalityCritical
+			r.When.MaxCriticality = types.CriticalityLow
+		}), true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := ValidateResponsePolicy(tc.policy); (err != nil) != tc.wantErr {
+				t.Errorf("ValidateResponsePolicy() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadShippedPolicy(t *testing.T) {
+	policy, err := LoadResponsePolicy("../policies/cautious.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.Name == "" || len(policy.Rules) == 0 {
+		t.Errorf("LoadResponsePolicy() = %+v, want a named policy with rules", policy)
+	}
+}
@@ -0,0 +1,274 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+	"github.com/nats-io/nats.go"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// PRIORITY & PREEMPTION (Section 4.4)
+// Tasks carry a Priority that priority_change triggers can move. Open tasks are
+// offered to bidders highest priority first, and under the engine's
+// PreemptionPolicy a saturated agent's lowest-priority in-flight task is
+// checkpointed and re-delegated to make room. Preemption is not the agent's
+// fault, so it carries no reputation penalty.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// BiddingQueue returns tasks open for bidding, highest priority first and,
+// within a priority, longest waiting first. A limit of 0 returns them all.
+func (e *Engine) BiddingQueue(limit int) ([]t.TaskSpec, error) {
+	catalog, err := e.loadTaskCatalog()
+	if err != nil {
+		return nil, err
+	}
+	
+	var open []taskIndexEntry
+	for _, entry := range catalog {
+		if entry.Status == t.TaskBidding {
+			open = append(open, entry)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		if open[i].Priority != open[j].Priority {
+			return open[i].Priority > open[j].Priority
+		}
+		if !open[i].StatusSince.Equal(open[j].StatusSince) {
+			return open[i].StatusSince.Before(open[j].StatusSince)
+		}
+		return open[i].TaskID < open[j].TaskID
+	})
+	if limit > 0 && len(open) > limit {
+		open = open[:limit]
+	}
+	return e.indexedTasks(open)
+}
+
+// SetTaskPriority changes the priority of a task and of its unfinished descendants.
+func (e *Engine) SetTaskPriority(taskID string, priority int) error {
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	for _, childID := range task.SubTaskIDs {
+		if err := e.SetTaskPriority(childID, priority); err != nil {
+			return err
+		}
+	}
+	if isTerminal(task.Status) || task.Priority == priority {
+		return nil
+	}
+	
+	log.Printf("Task %s priority %d → %d", taskID, task.Priority, priority)
+	task.Priority = priority
+	return e.UpdateTask(*task)
+}
+
+// applyPriorityShift handles a priority_change trigger.
+func (e *Engine) applyPriorityShift(task *t.TaskSpec, trigger t.AdaptiveTrigger) error {
+	if trigger.NewPriority == nil {
+		return fmt.Errorf("priority trigger %s on task %s carries no new priority", trigger.TriggerID, task.TaskID)
+	}
+	return e.SetTaskPriority(task.TaskID, *trigger.NewPriority)
+}
+
+// PreemptFor frees a slot on agentID for taskID by preempting the agent's
+// lowest-priority in-flight task. Returns the preempted task's ID, or "" if
+// the agent has spare capacity or nothing is low enough to preempt.
+func (e *Engine) PreemptFor(taskID, agentID string) (string, error) {
+	if !e.Preemption.Enabled {
+		return "", fmt.Errorf("preemption is disabled")
+	}
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return "", err
+	}
+	agent, err := e.GetAgent(agentID)
+	if err != nil {
+		return "", fmt.Errorf("get agent %s: %w", agentID, err)
+	}
+	
+	inFlight, err := e.agentInFlight(agentID)
+	if err != nil {
+		return "", err
+	}
+	load := agent.CurrentLoad
+	if len(inFlight) > load {
+		load = len(inFlight)
+	}
+	if agent.MaxLoad <= 0 || load < agent.MaxLoad {
+		return "", nil
+	}
+	
+	// Every in-flight task counts toward the agent's load, but only tasks this
+	// engine delegated may be preempted
+	victim := selectPreemptionVictim(delegatedBy(inFlight, e.SelfID), task.Priority, e.Preemption.MinPriorityGap)
+	if victim == nil {
+		log.Printf("Agent %s saturated (%d/%d) but no task yields to %s (priority %d)",
+			agentID, load, agent.MaxLoad, taskID, task.Priority)
+		return "", nil
+	}
+	if err := e.preempt(victim, task); err != nil {
+		return "", err
+	}
+	return victim.TaskID, nil
+}
+
+// agentInFlight lists the tasks currently occupying an agent's capacity.
+func (e *Engine) agentInFlight(agentID string) ([]t.TaskSpec, error) {
+	catalog, err := e.loadTaskCatalog()
+	if err != nil {
+		return nil, err
+	}
+	var busy []taskIndexEntry
+	for _, entry := range catalog {
+		if entry.DelegateeID != agentID {
+			continue
+		}
+		switch entry.Status {
+		case t.TaskAssigned, t.TaskInProgress, t.TaskCheckpoint, t.TaskPaused:
+			busy = append(busy, entry)
+		}
+	}
+	return e.indexedTasks(busy)
+}
+
+// delegatedBy keeps the tasks whose delegator is delegatorID.
+func delegatedBy(tasks []t.TaskSpec, delegatorID string) []t.TaskSpec {
+	var own []t.TaskSpec
+	for _, task := range tasks {
+		if task.DelegatorID == delegatorID {
+			own = append(own, task)
+		}
+	}
+	return own
+}
+
+// selectPreemptionVictim picks the lowest-priority task at least gap below
+// priority; ties go to the most recently started, which has the least sunk work.
+func selectPreemptionVictim(tasks []t.TaskSpec, priority, gap int) *t.TaskSpec {
+	if gap < 1 {
+		gap = 1
+	}
+	var victim *t.TaskSpec
+	for i := range tasks {
+		cand := &tasks[i]
+		if cand.Priority > priority-gap {
+			continue
+		}
+		if victim == nil || cand.Priority < victim.Priority ||
+			(cand.Priority == victim.Priority && startedAfter(cand, victim)) {
+			victim = cand
+		}
+	}
+	return victim
+}
+
+func startedAfter(a, b *t.TaskSpec) bool {
+	if a.StartedAt == nil || b.StartedAt == nil {
+		return a.StartedAt != nil
+	}
+	return a.StartedAt.After(*b.StartedAt)
+}
+
+// preempt asks the victim's delegatee to checkpoint, releases the delegatee
+// without a reputation penalty and re-publishes the victim for bidding.
+func (e *Engine) preempt(victim, by *t.TaskSpec) error {
+	prevAgent := victim.DelegateeID
+	log.Printf("PREEMPTING task %s (priority %d) on %s for %s (priority %d)",
+		victim.TaskID, victim.Priority, prevAgent, by.TaskID, by.Priority)
+	
+	if cp, err := e.requestPreemptionCheckpoint(victim, by); err != nil {
+		log.Printf("No checkpoint from %s for preempted task %s: %v", prevAgent, victim.TaskID, err)
+	} else if e.Preemption.TrustCheckpoint {
+		if err := e.VerifyCheckpoint(victim.TaskID, cp.WriterID, cp.Sequence, e.SelfID, true); err != nil {
+			log.Printf("Verify preemption checkpoint for %s: %v", victim.TaskID, err)
+		}
+	}
+	
+	if victim.ContractID != "" {
+		if err := e.terminateContract(victim.ContractID); err != nil {
+			log.Printf("Terminate contract %s: %v", victim.ContractID, err)
+		}
+	}
+	
+	victim.DelegateeID = ""
+	victim.ContractID = ""
+	victim.DelegationChain = nil
+	victim.Status = t.TaskReAllocating
+	if err := e.UpdateTask(*victim); err != nil {
+		return err
+	}
+	if err := e.adjustAgentLoad(prevAgent, -1); err != nil {
+		log.Printf("Release load on %s after preempting %s: %v", prevAgent, victim.TaskID, err)
+	}
+	
+	err := e.EmitMonitorEvent(t.MonitorEvent{
+		EventID:   fmt.Sprintf("preempt_%s_%d", victim.TaskID, time.Now().UnixNano()),
+		TaskID:    victim.TaskID,
+		AgentID:   prevAgent,
+		EventType: t.EventResourceWarning,
+		Severity:  t.CriticalityLow,
+		Message:   fmt.Sprintf("preempted on %s by %s (priority %d)", prevAgent, by.TaskID, by.Priority),
+	})
+	if err != nil {
+		log.Printf("Preemption event for %s: %v", victim.TaskID, err)
+	}
+	
+	_, err = e.PublishTaskForBidding(*victim)
+	return err
+}
+
+// requestPreemptionCheckpoint sends the preemption notice and stores the
+// checkpoint the delegatee replies with.
+func (e *Engine) requestPreemptionCheckpoint(victim, by *t.TaskSpec) (*t.Checkpoint, error) {
+	channelName, err := e.SetupAgentChannel(victim.TaskID, victim.DelegateeID)
+	if err != nil {
+		return nil, err
+	}
+	notice := t.PreemptionNotice{
+		TaskID:   victim.TaskID,
+		ByTaskID: by.TaskID,
+		Priority: by.Priority,
+		IssuedAt: time.Now(),
+	}
+	data, err := e.requestAgentMessage(channelName, victim.TaskID, t.MsgPreempted, notice, e.FailoverTimeout)
+	if err != nil {
+		return nil, err
+	}
+	
+	var reply t.Checkpoint
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, fmt.Errorf("unmarshal preemption checkpoint: %w", err)
+	}
+	return e.recordCheckpoint(victim.TaskID, victim.DelegateeID, reply.Artifact, reply.Progress, reply.Metadata)
+}
+
+// ServePreemptionNotices is the delegatee's side of preemption. It listens on
+// the agent channel for the given task and answers a notice with the checkpoint
+// release returns; the delegatee is expected to stop work on the task there.
+func (e *Engine) ServePreemptionNotices(taskID, delegatorID string, release func(t.PreemptionNotice) t.Checkpoint) error {
+	channelName := agentChannelName(taskID, delegatorID, e.SelfID)
+	return e.subscribeAgentChannel(channelName, "preempt", func(env t.AgentMessage, msg *nats.Msg) {
+		if env.Type != t.MsgPreempted {
+			return
+		}
+		var notice t.PreemptionNotice
+		if err := json.Unmarshal(env.Payload, &notice); err != nil {
+			log.Printf("Malformed preemption notice on %s: %v", channelName, err)
+			return
+		}
+		cp := release(notice)
+		cp.TaskID = notice.TaskID
+		cp.AgentID = e.SelfID
+		body, _ := json.Marshal(cp)
+		if err := msg.Respond(body); err != nil {
+			log.Printf("Preemption reply on %s: %v", channelName, err)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	
+	"github.com/dataparency-dev/AI-delegation/types"
+)
+
+func TestDelegatedBy(t *testing.T) {
+	tasks := []types.TaskSpec{
+		{TaskID: "a", DelegatorID: "me"},
+		{TaskID: "b", DelegatorID: "other"},
+		{TaskID: "c", DelegatorID: "me"},
+	}
+	tests := []struct {
+		delegator string
+		want      []string
+	}{
+		{"me", []string{"a", "c"}},
+		{"other", []string{"b"}},
+		{"nobody", nil},
+	}
+	for _, tc := range tests {
+		var got []string
+		for _, task := range delegatedBy(tasks, tc.delegator) {
+			got = append(got, task.TaskID)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("delegatedBy(%q) = %v, want %v", tc.delegator, got, tc.want)
+		}
+	}
+}
+
+func TestSelectPreemptionVictim(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	task := func(id string, priority int, startedAfter time.Duration) types.TaskSpec {
+		task := types.TaskSpec{TaskID: id, Priority: priority}
+		if startedAfter >= 0 {
+			started := base.Add(startedAfter)
+			task.StartedAt = &started
+		}
+		return task
+	}
+	const unstarted = -1
+	running := []types.TaskSpec{
+		task("p5", 5, 0),
+		task("p2-early", 2, time.Minute),
+		task("p2-late", 2, time.Hour),
+		task("p7", 7, 0),
+	}
+	tests := []struct {
+		name     string
+		tasks    []types.TaskSpec
+		priority int
+		gap      int
+		want     string // Empty for no victim
+	}{
+		{"lowest priority, most recently started", running, 8, 1, "p2-late"},
+		{"exactly gap below", running, 4, 2, "p2-late"},
+		{"nothing far enough below", running, 3, 2, ""},
+		{"gap below one counts as one", running, 3, 0, "p2-late"},
+		{"equal priority is never preempted", running, 2, 0, ""},
+		{"started beats unstarted", []types.TaskSpec{task("idle", 1, unstarted), task("busy", 1, 0)}, 5, 1, "busy"},
+		{"started beats unstarted in any order", []types.TaskSpec{task("busy", 1, 0), task("idle", 1, unstarted)}, 5, 1, "busy"},
+		{"no tasks", nil, 5, 1, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ""
+			if victim := selectPreemptionVictim(tc.tasks, tc.priority, tc.gap); victim != nil {
+				got = victim.TaskID
+			}
+			if got != tc.want {
+				t.Errorf("selectPreemptionVictim() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// DELEGATION CHAIN PROVENANCE (Sections 4.6, 4.7)
+// Every assigned task carries the chain of hand-offs that led to it, A→B→C,
+// with the contract behind each hop. The chain is rebuilt and validated on
+// every hand-off: it must be contiguous, may not hand work back to an agent
+// already in it, and may not grow past MaxChainLength.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// DefaultMaxChainLength allows one link per permitted delegation hop plus the
+// original delegator's own hand-off.
+const DefaultMaxChainLength = DefaultMaxDelegationDepth + 1
+
+// GetDelegationChain returns the chain of hand-offs for a task, root first.
+func (e *Engine) GetDelegationChain(taskID string) ([]t.DelegationLink, error) {
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return task.DelegationChain, nil
+}
+
+// extendChain builds the chain a task will carry once delegateeID takes it
+// under contractID, and validates it.
+func (e *Engine) extendChain(task *t.TaskSpec, delegateeID, contractID string) ([]t.DelegationLink, error) {
+	inherited, err := e.inheritedChain(task)
+	if err != nil {
+		return nil, err
+	}
+	chain := append(inherited, t.DelegationLink{
+		TaskID:      task.TaskID,
+		DelegatorID: task.DelegatorID,
+		DelegateeID: delegateeID,
+		ContractID:  contractID,
+		LinkedAt:    time.Now(),
+	})
+	if err := e.validateChain(chain); err != nil {
+		return nil, fmt.Errorf("task %s: %w", task.TaskID, err)
+	}
+	return chain, nil
+}
+
+// inheritedChain is the part of the parent's chain that ends with this task's
+// delegator. Root tasks, and children decomposed by an unassigned parent's own
+// delegator, start an empty chain. A parent that cannot be read is an error:
+// an empty chain would hide the hops above it from the length and loop checks.
+func (e *Engine) inheritedChain(task *t.TaskSpec) ([]t.DelegationLink, error) {
+	if task.ParentTaskID == "" {
+		return nil, nil
+	}
+	parent, err := e.GetTask(task.ParentTaskID)
+	if err != nil {
+		return nil, fmt.Errorf("parent %s of task %s: %w", task.ParentTaskID, task.TaskID, err)
+	}
+	for i := len(parent.DelegationChain) - 1; i >= 0; i-- {
+		if parent.DelegationChain[i].DelegateeID == task.DelegatorID {
+			return append([]t.DelegationLink{}, parent.DelegationChain[:i+1]...), nil
+		}
+	}
+	return nil, nil
+}
+
+// validateChain checks length, continuity and loops.
+func (e *Engine) validateChain(chain []t.DelegationLink) error {
+	maxLen := e.MaxChainLength
+	if maxLen <= 0 {
+		maxLen = DefaultMaxChainLength
+	}
+	if len(chain) > maxLen {
+		return fmt.Errorf("delegation chain of %d links exceeds limit %d", len(chain), maxLen)
+	}
+	
+	seen := make(map[string]bool, len(chain)+1)
+	for i, link := range chain {
+		if i == 0 {
+			seen[link.DelegatorID] = true
+		} else if link.DelegatorID != chain[i-1].DelegateeID {
+			return fmt.Errorf("delegation chain broken at %s: %s delegated but %s held the work",
+				link.TaskID, link.DelegatorID, chain[i-1].DelegateeID)
+		}
+		if seen[link.DelegateeID] {
+			return fmt.Errorf("delegation loop: %s would delegate back to ancestor %s",
+				link.DelegatorID, link.DelegateeID)
+		}
+		seen[link.DelegateeID] = true
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+	
+	"github.com/dataparency-dev/AI-delegation/types"
+)
+
+func TestValidateChain(t *testing.T) {
+	link := func(task, from, to string) types.DelegationLink {
+		return types.DelegationLink{TaskID: task, DelegatorID: from, DelegateeID: to}
+	}
+	// chainOf links n agents in a row: agent-0 → agent-1 → … → agent-n
+	chainOf := func(n int) []types.DelegationLink {
+		var chain []types.DelegationLink
+		for i := 0; i < n; i++ {
+			chain = append(chain, link(fmt.Sprintf("t%d", i), fmt.Sprintf("agent-%d", i), fmt.Sprintf("agent-%d", i+1)))
+		}
+		return chain
+	}
+	tests := []struct {
+		name    string
+		limit   int
+		chain   []types.DelegationLink
+		wantErr bool
+	}{
+		{"empty", 3, nil, false},
+		{"continuous", 3, []types.DelegationLink{link("t1", "a", "b"), link("t2", "b", "c")}, false},
+		{"at the limit", 3, chainOf(3), false},
+		{"over the limit", 3, chainOf(4), true},
+		{"default limit", 0, chainOf(DefaultMaxChainLength), false},
+		{"over the default limit", 0, chainOf(DefaultMaxChainLength + 1), true},
+		{"broken", 3, []types.DelegationLink{link("t1", "a", "b"), link("t2", "c", "d")}, true},
+		{"back to the root", 3, []types.DelegationLink{link("t1", "a", "b"), link("t2", "b", "a")}, true},
+		{"back to the middle", 4, []types.DelegationLink{link("t1", "a", "b"), link("t2", "b", "c"), link("t3", "c", "b")}, true},
+		{"to itself", 3, []types.DelegationLink{link("t1", "a", "a")}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &Engine{MaxChainLength: tc.limit}
+			if err := e.validateChain(tc.chain); (err != nil) != tc.wantErr {
+				t.Errorf("validateChain() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// DefaultPageSize applies when a query leaves Limit unset.
+const DefaultPageSize = 50
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// QUERIES BY PARTY, STATUS AND TIME RANGE
+// The store only fetches by exact domain/entity/aspect, so the engine maintains
+// catalog indexes alongside every task and contract write, one shard per engine:
+//   Tasks/index/catalog_{engine}     → map[task_id]taskIndexEntry (a projection)
+//   Contracts/index/catalog_{engine} → map[contract_id]contractIndexEntry
+//   {domain}/index/catalog_writers   → []engine ID
+// Readers merge the shards, keeping the most recently written row per ID.
+// Task rows hold only what queries filter and sort on; matching specs are read
+// afterwards, one page at a time. The spec is the source of truth, so a failed
+// index write is logged rather than failing the task write.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// taskIndexEntry is a catalog row: the fields queries use from the latest spec,
+// plus when its status last changed.
+type taskIndexEntry struct {
+	TaskID       string       `json:"task_id"`
+	Status       t.TaskStatus `json:"status"`
+	DelegatorID  string       `json:"delegator_id"`
+	DelegateeID  string       `json:"delegatee_id,omitempty"`
+	ParentTaskID string       `json:"parent_task_id,omitempty"`
+	Priority     int          `json:"priority"`
+	CreatedAt    time.Time    `json:"created_at"`
+	StartedAt    *time.Time   `json:"started_at,omitempty"`
+	StatusSince  time.Time    `json:"status_since"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// projectTask copies the indexed fields of a spec into a catalog row.
+func projectTask(task t.TaskSpec) taskIndexEntry {
+	return taskIndexEntry{
+		TaskID:       task.TaskID,
+		Status:       task.Status,
+		DelegatorID:  task.DelegatorID,
+		DelegateeID:  task.DelegateeID,
+		ParentTaskID: task.ParentTaskID,
+		Priority:     task.Priority,
+		CreatedAt:    task.CreatedAt,
+		StartedAt:    task.StartedAt,
+	}
+}
+
+// contractIndexEntry is a contract catalog row.
+type contractIndexEntry struct {
+	Contract  t.DelegationContract `json:"contract"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// QueryTasks returns one page of tasks matching the query, oldest first.
+func (e *Engine) QueryTasks(q t.TaskQuery) (*t.TaskPage, error) {
+	catalog, err := e.loadTaskCatalog()
+	if err != nil {
+		return nil, err
+	}
+	
+	var matches []taskIndexEntry
+	for _, entry := range catalog {
+		if matchTask(q, entry) {
+			matches = append(matches, entry)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].CreatedAt.Equal(matches[j].CreatedAt) {
+			return matches[i].CreatedAt.Before(matches[j].CreatedAt)
+		}
+		return matches[i].TaskID < matches[j].TaskID
+	})
+	
+	lo, hi, next := pageBounds(len(matches), q.Offset, q.Limit)
+	tasks, err := e.indexedTasks(matches[lo:hi])
+	if err != nil {
+		return nil, err
+	}
+	return &t.TaskPage{Tasks: tasks, Total: len(matches), NextOffset: next}, nil
+}
+
+// indexedTasks reads the specs behind catalog rows, in order.
+func (e *Engine) indexedTasks(entries []taskIndexEntry) ([]t.TaskSpec, error) {
+	tasks := make([]t.TaskSpec, 0, len(entries))
+	for _, entry := range entries {
+		task, err := e.GetTask(entry.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("indexed task %s: %w", entry.TaskID, err)
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, nil
+}
+
+// QueryContracts returns one page of contracts matching the query, oldest first.
+func (e *Engine) QueryContracts(q t.ContractQuery) (*t.ContractPage, error) {
+	catalog, err := e.loadContractCatalog()
+	if err != nil {
+		return nil, err
+	}
+	
+	var matches []t.DelegationContract
+	for _, c := range catalog {
+		if matchContract(q, c) {
+			matches = append(matches, c)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].CreatedAt.Equal(matches[j].CreatedAt) {
+			return matches[i].CreatedAt.Before(matches[j].CreatedAt)
+		}
+		return matches[i].ContractID < matches[j].ContractID
+	})
+	
+	lo, hi, next := pageBounds(len(matches), q.Offset, q.Limit)
+	return &t.ContractPage{Contracts: matches[lo:hi], Total: len(matches), NextOffset: next}, nil
+}
+
+func matchTask(q t.TaskQuery, task taskIndexEntry) bool {
+	switch {
+	case q.DelegatorID != "" && task.DelegatorID != q.DelegatorID:
+		return false
+	case q.DelegateeID != "" && task.DelegateeID != q.DelegateeID:
+		return false
+	case q.ParentTaskID != "" && task.ParentTaskID != q.ParentTaskID:
+		return false
+	case !q.CreatedAfter.IsZero() && task.CreatedAt.Before(q.CreatedAfter):
+		return false
+	case !q.CreatedBefore.IsZero() && !task.CreatedAt.Before(q.CreatedBefore):
+		return false
+	case !q.StatusChangedBefore.IsZero() && !task.StatusSince.Before(q.StatusChangedBefore):
+		return false
+	}
+	if len(q.Statuses) == 0 {
+		return true
+	}
+	for _, s := range q.Statuses {
+		if task.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+func matchContract(q t.ContractQuery, c t.DelegationContract) bool {
+	switch {
+	case q.DelegatorID != "" && c.DelegatorID != q.DelegatorID:
+		return false
+	case q.DelegateeID != "" && c.DelegateeID != q.DelegateeID:
+		return false
+	case q.PartyID != "" && c.DelegatorID != q.PartyID && c.DelegateeID != q.PartyID:
+		return false
+	case q.TaskID != "" && c.TaskID != q.TaskID:
+		return false
+	case !q.CreatedAfter.IsZero() && c.CreatedAt.Before(q.CreatedAfter):
+		return false
+	case !q.CreatedBefore.IsZero() && !c.CreatedAt.Before(q.CreatedBefore):
+		return false
+	}
+	if len(q.Statuses) == 0 {
+		return true
+	}
+	for _, s := range q.Statuses {
+		if c.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// pageBounds clamps offset/limit to n results and computes the next offset.
+func pageBounds(n, offset, limit int) (lo, hi, next int) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	lo = offset
+	if lo > n {
+		lo = n
+	}
+	hi = lo + limit
+	if hi > n {
+		hi = n
+	}
+	next = -1
+	if hi < n {
+		next = hi
+	}
+	return lo, hi, next
+}
+
+// indexTask upserts a task's projection into this engine's catalog shard,
+// tracking when its status changed. Other engines' shards are only read for a
+// task this engine has not indexed before.
+func (e *Engine) indexTask(task t.TaskSpec) error {
+	e.indexMu.Lock()
+	defer e.indexMu.Unlock()
+	
+	if err := e.registerWriter(DomainTasks, "index", "catalog"); err != nil {
+		return err
+	}
+	shard := make(map[string]taskIndexEntry)
+	if _, err := e.retrieveJSON(DomainTasks, "index", e.shardAspect("catalog"), &shard); err != nil {
+		return err
+	}
+	
+	prev, ok := shard[task.TaskID]
+	if !ok {
+		merged, err := e.loadTaskCatalog()
+		if err != nil {
+			return err
+		}
+		prev, ok = merged[task.TaskID]
+	}
+	entry := projectTask(task)
+	entry.StatusSince = prev.StatusSince
+	if !ok || prev.Status != task.Status {
+		entry.StatusSince = time.Now()
+	}
+	entry.UpdatedAt = time.Now()
+	shard[task.TaskID] = entry
+	
+	body, err := json.Marshal(shard)
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainTasks, "index", e.shardAspect("catalog"), body)
+}
+
+// indexContract upserts a contract into this engine's catalog shard.
+func (e *Engine) indexContract(contract t.DelegationContract) error {
+	e.indexMu.Lock()
+	defer e.indexMu.Unlock()
+	
+	if err := e.registerWriter(DomainContracts, "index", "catalog"); err != nil {
+		return err
+	}
+	shard := make(map[string]contractIndexEntry)
+	if _, err := e.retrieveJSON(DomainContracts, "index", e.shardAspect("catalog"), &shard); err != nil {
+		return err
+	}
+	shard[contract.ContractID] = contractIndexEntry{Contract: contract, UpdatedAt: time.Now()}
+	
+	body, err := json.Marshal(shard)
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainContracts, "index", e.shardAspect("catalog"), body)
+}
+
+// loadTaskCatalog merges every engine's task catalog shard; a catalog nobody
+// has written yet is empty. Read failures are returned.
+func (e *Engine) loadTaskCatalog() (map[string]taskIndexEntry, error) {
+	writers, err := e.shardWriters(DomainTasks, "index", "catalog")
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(map[string]taskIndexEntry)
+	for _, w := range writers {
+		shard := make(map[string]taskIndexEntry)
+		if _, err := e.retrieveJSON(DomainTasks, "index", "catalog_"+w, &shard); err != nil {
+			return nil, fmt.Errorf("task catalog shard %s: %w", w, err)
+		}
+		for id, entry := range shard {
+			entry.TaskID = id
+			if cur, ok := catalog[id]; !ok || entry.UpdatedAt.After(cur.UpdatedAt) {
+				catalog[id] = entry
+			}
+		}
+	}
+	return catalog, nil
+}
+
+// loadContractCatalog merges every engine's contract catalog shard; a catalog
+// nobody has written yet is empty. Read failures are returned.
+func (e *Engine) loadContractCatalog() (map[string]t.DelegationContract, error) {
+	writers, err := e.shardWriters(DomainContracts, "index", "catalog")
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]contractIndexEntry)
+	for _, w := range writers {
+		shard := make(map[string]contractIndexEntry)
+		if _, err := e.retrieveJSON(DomainContracts, "index", "catalog_"+w, &shard); err != nil {
+			return nil, fmt.Errorf("contract catalog shard %s: %w", w, err)
+		}
+		for id, entry := range shard {
+			if cur, ok := latest[id]; !ok || entry.UpdatedAt.After(cur.UpdatedAt) {
+				latest[id] = entry
+			}
+		}
+	}
+	catalog := make(map[string]t.DelegationContract, len(latest))
+	for id, entry := range latest {
+		catalog[id] = entry.Contract
+	}
+	return catalog, nil
+}
@@ -0,0 +1,299 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	
+	"github.com/awgh/bencrypt/ecc"
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// MONITORING PRIVACY (Section 4.5 — privacy dimension)
+// Before an event leaves the delegatee, a Redactor scrubs PII from its message
+// and withholds fields the policy does not allow. Named observers may see more
+// through their own per-event view, and the unredacted event can be kept for
+// the delegator. Both are read back with the encryptDataPKey Get flag set to
+// the reader's published key. That flag only protects the read, so they are
+// also sealed to the same key before they are stored: the store never holds
+// them in plaintext. Consumers of the public event check Withholds before
+// trusting a zero progress or spend.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// Monitoring event fields a RedactionPolicy may withhold.
+const (
+	FieldSeverity    = "severity"
+	FieldProgress    = "progress"
+	FieldResourceUse = "resource_use"
+	FieldMessage     = "message"
+)
+
+var redactableFields = []string{FieldSeverity, FieldProgress, FieldResourceUse, FieldMessage}
+
+// DefaultPIIPatterns returns patterns for e-mail addresses, phone numbers,
+// US social security numbers, payment card numbers and IPv4 addresses.
+func DefaultPIIPatterns() []t.RedactionPattern {
+	return []t.RedactionPattern{
+		{Name: "email", Pattern: `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`},
+		{Name: "ssn", Pattern: `\b\d{3}-\d{2}-\d{4}\b`},
+		{Name: "card", Pattern: `\b(?:\d[ \-]?){13,16}\b`},
+		{Name: "phone", Pattern: `\+?\d{1,3}?[ .\-]?\(?\d{3}\)?[ .\-]\d{3}[ .\-]\d{4}\b`},
+		{Name: "ipv4", Pattern: `\b(?:\d{1,3}\.){3}\d{1,3}\b`},
+	}
+}
+
+// Redactor applies a RedactionPolicy to monitoring events.
+type Redactor struct {
+	policy   t.RedactionPolicy
+	patterns []*regexp.Regexp // Compiled policy.Patterns, same order
+}
+
+// NewRedactor compiles a policy's patterns.
+func NewRedactor(policy t.RedactionPolicy) (*Redactor, error) {
+	r := &Redactor{policy: policy}
+	for _, p := range policy.Patterns {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile redaction pattern %s: %w", p.Name, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Applies reports whether the policy covers a task.
+func (r *Redactor) Applies(task *t.TaskSpec) bool {
+	return task == nil || task.ContextSensitivity >= r.policy.MinSensitivity
+}
+
+// Redact returns the copy of an event an observer may see; an empty observer
+// ID gives the view every observer gets. Redacted lists what was changed.
+func (r *Redactor) Redact(event t.MonitorEvent, observerID string) t.MonitorEvent {
+	out := event
+	out.Redacted = nil
+	
+	allowed := make(map[string]bool)
+	for _, f := range r.policy.AllowedFields {
+		allowed[f] = true
+	}
+	for _, f := range r.policy.ObserverFields[observerID] {
+		allowed[f] = true
+	}
+	
+	for _, field := range redactableFields {
+		if len(r.policy.AllowedFields) == 0 || allowed[field] {
+			continue
+		}
+		switch field {
+		case FieldSeverity:
+			out.Severity = ""
+		case FieldProgress:
+			out.Progress = 0
+		case FieldResourceUse:
+			out.ResourceUse = 0
+		case FieldMessage:
+			out.Message = ""
+		}
+		out.Redacted = append(out.Redacted, field)
+	}
+	
+	if out.Message != "" {
+		scrubbed := out.Message
+		for i, re := range r.patterns {
+			replacement := r.policy.Patterns[i].Replacement
+			if replacement == "" {
+				replacement = fmt.Sprintf("[REDACTED:%s]", r.policy.Patterns[i].Name)
+			}
+			scrubbed = re.ReplaceAllLiteralString(scrubbed, replacement)
+		}
+		if scrubbed != out.Message {
+			out.Message = scrubbed
+			out.Redacted = append(out.Redacted, FieldMessage)
+		}
+	}
+	return out
+}
+
+// SetEncryptionKey sets the key this engine opens sealed monitoring payloads
+// with and publishes its public half on the engine's agent profile, so
+// delegatees can seal events to it.
+func (e *Engine) SetEncryptionKey(key *ecc.KeyPair) error {
+	e.EncryptionKey = key
+	agent, err := e.GetAgent(e.SelfID)
+	if err != nil {
+		return fmt.Errorf("get agent %s: %w", e.SelfID, err)
+	}
+	agent.EncryptionKey = key.GetPubKey().ToB64()
+	return e.saveAgent(*agent)
+}
+
+// redactForDisclosure returns the event every observer may see. When the
+// policy changed anything it also stores each named observer's view and, if
+// asked, the original, each sealed to its reader. A reader with no published
+// key gets nothing rather than a plaintext copy.
+func (e *Engine) redactForDisclosure(event t.MonitorEvent) (t.MonitorEvent, error) {
+	if e.Redactor == nil {
+		return event, nil
+	}
+	task, _ := e.GetTask(event.TaskID)
+	if !e.Redactor.Applies(task) {
+		return event, nil
+	}
+	
+	public := e.Redactor.Redact(event, "")
+	if len(public.Redacted) == 0 {
+		return public, nil
+	}
+	
+	for observerID := range e.Redactor.policy.ObserverFields {
+		body, err := e.sealFor(observerID, e.Redactor.Redact(event, observerID))
+		if err != nil {
+			log.Printf("No view of %s for %s: %v", event.EventID, observerID, err)
+			continue
+		}
+		if err := e.storeData(DomainMonitoring, event.TaskID, viewAspect(observerID, event.EventID), body); err != nil {
+			return public, fmt.Errorf("store view for %s: %w", observerID, err)
+		}
+	}
+	
+	if e.Redactor.policy.SealOriginal {
+		if task == nil {
+			log.Printf("Original of %s not kept: task %s unreadable", event.EventID, event.TaskID)
+			return public, nil
+		}
+		body, err := e.sealFor(task.DelegatorID, event)
+		if err != nil {
+			log.Printf("Original of %s not kept: %v", event.EventID, err)
+			return public, nil
+		}
+		if err := e.storeData(DomainMonitoring, event.TaskID, "sealed_"+event.EventID, body); err != nil {
+			return public, fmt.Errorf("seal event %s: %w", event.EventID, err)
+		}
+	}
+	return public, nil
+}
+
+// sealFor encrypts v to the key agentID published with SetEncryptionKey and
+// returns it as a JSON string for storage.
+func (e *Engine) sealFor(agentID string, v interface{}) ([]byte, error) {
+	agent, err := e.GetAgent(agentID)
+	if err != nil {
+		return nil, fmt.Errorf("get agent %s: %w", agentID, err)
+	}
+	if agent.EncryptionKey == "" {
+		return nil, fmt.Errorf("agent %s has published no encryption key", agentID)
+	}
+	pub := new(ecc.PubKey)
+	if err := pub.FromB64(agent.EncryptionKey); err != nil {
+		return nil, fmt.Errorf("encryption key of %s: %w", agentID, err)
+	}
+	clear, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := new(ecc.KeyPair).EncryptMessage(clear, pub)
+	if err != nil {
+		return nil, fmt.Errorf("seal to %s: %w", agentID, err)
+	}
+	return json.Marshal(sealed)
+}
+
+// retrieveSealed reads data stored by sealFor for this engine. The Get asks the
+// backend to encrypt the response to this engine's key, which is removed here
+// before the stored seal is opened into v.
+func (e *Engine) retrieveSealed(taskID, aspect string, v interface{}) error {
+	if e.EncryptionKey == nil {
+		return fmt.Errorf("engine %s has no encryption key", e.SelfID)
+	}
+	data, err := e.retrieveEncrypted(DomainMonitoring, taskID, aspect, e.EncryptionKey.GetPubKey().ToB64())
+	if err != nil {
+		return err
+	}
+	_, stored, err := e.EncryptionKey.DecryptMessage(data)
+	if err != nil {
+		return fmt.Errorf("open %s response: %w", aspect, err)
+	}
+	return e.openSealed(stored, v)
+}
+
+// openSealed decrypts data stored by sealFor into v with this engine's key.
+func (e *Engine) openSealed(data []byte, v interface{}) error {
+	if e.EncryptionKey == nil {
+		return fmt.Errorf("engine %s has no encryption key", e.SelfID)
+	}
+	var sealed []byte
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return fmt.Errorf("unmarshal sealed payload: %w", err)
+	}
+	_, clear, err := e.EncryptionKey.DecryptMessage(sealed)
+	if err != nil {
+		return fmt.Errorf("open sealed payload: %w", err)
+	}
+	return json.Unmarshal(clear, v)
+}
+
+// GetMonitorView returns the latest event as a named observer may see it,
+// falling back to the view every observer gets.
+func (e *Engine) GetMonitorView(taskID, observerID string) (*t.MonitorEvent, error) {
+	latest, err := e.GetLatestMonitorEvent(taskID)
+	if err != nil {
+		return nil, err
+	}
+	view, err := e.GetEventView(taskID, latest.EventID, observerID)
+	if isNotFound(err) {
+		return latest, nil
+	}
+	return view, err
+}
+
+// GetEventView returns one event as a named observer was allowed to see it. A
+// named view is sealed to its observer, so only that observer's engine can
+// read it; an event with no view for the observer is not found.
+func (e *Engine) GetEventView(taskID, eventID, observerID string) (*t.MonitorEvent, error) {
+	if observerID != e.SelfID {
+		return nil, fmt.Errorf("views of task %s are sealed to %s", taskID, observerID)
+	}
+	var event t.MonitorEvent
+	if err := e.retrieveSealed(taskID, viewAspect(observerID, eventID), &event); err != nil {
+		return nil, fmt.Errorf("view of %s for %s: %w", eventID, observerID, err)
+	}
+	return &event, nil
+}
+
+// GetSealedEvent reads back an unredacted event. It is sealed to the task
+// delegator's published key and read through encryptDataPKey, so only the
+// delegator's engine can open it.
+func (e *Engine) GetSealedEvent(taskID, eventID string) (*t.MonitorEvent, error) {
+	var event t.MonitorEvent
+	if err := e.retrieveSealed(taskID, "sealed_"+eventID, &event); err != nil {
+		return nil, fmt.Errorf("sealed event %s: %w", eventID, err)
+	}
+	return &event, nil
+}
+
+// viewAspect is where an observer's view of one event is stored.
+func viewAspect(observerID, eventID string) string {
+	return "view_" + observerID + "_" + eventID
+}
+
+// latestFigures returns a task's latest event with progress and spend as
+// complete as this engine may see them: the sealed original when the public
+// copy withholds either and this engine can open it, else the public copy.
+// Callers still check Withholds before trusting a zero.
+func (e *Engine) latestFigures(taskID string) (*t.MonitorEvent, error) {
+	event, err := e.GetLatestMonitorEvent(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.Withholds(FieldProgress) && !event.Withholds(FieldResourceUse) {
+		return event, nil
+	}
+	if e.EncryptionKey != nil {
+		if original, err := e.GetSealedEvent(taskID, event.EventID); err == nil {
+			return original, nil
+		}
+	}
+	return event, nil
+}
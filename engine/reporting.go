@@ -0,0 +1,318 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+	nc "github.com/dataparency-dev/natsclient"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// CONTRACTED MONITORING MODES (Section 4.5)
+// The contract's MonitoringMode decides what leaves the delegatee and when:
+// continuous streams every event, periodic batches them per ReportingInterval,
+// event-triggered withholds routine progress and outcome-only reveals only how
+// the task ended. Withheld events still feed the local aggregator. Observers
+// flag events a mode should not have disclosed and reports that never arrive.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// MissedReportGrace is how many reporting intervals may pass without an event
+// before a continuous or periodic task is flagged.
+const MissedReportGrace = 2
+
+// reportBatch holds events waiting for the next periodic report.
+type reportBatch struct {
+	events []t.MonitorEvent
+	due    time.Time
+}
+
+// monitoringTerms resolves the mode and reporting interval that govern a
+// task: the active contract's terms, else the task's own mode. Tasks with no
+// mode are monitored continuously.
+func (e *Engine) monitoringTerms(taskID string) (t.MonitoringMode, time.Duration) {
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return t.MonitorContinuous, 0
+	}
+	mode := task.MonitoringMode
+	var interval time.Duration
+	if task.ContractID != "" {
+		if contract, err := e.GetContract(task.ContractID); err == nil {
+			if contract.Terms.MonitoringMode != "" {
+				mode = contract.Terms.MonitoringMode
+			}
+			interval = time.Duration(contract.Terms.ReportingInterval) * time.Second
+		}
+	}
+	if mode == "" {
+		mode = t.MonitorContinuous
+	}
+	return mode, interval
+}
+
+// queueReport adds an event to the task's periodic batch and returns the
+// events now due for disclosure. Outcomes flush the batch immediately.
+func (e *Engine) queueReport(event t.MonitorEvent, interval time.Duration) []t.MonitorEvent {
+	e.reportMu.Lock()
+	defer e.reportMu.Unlock()
+	
+	if e.batches == nil {
+		e.batches = make(map[string]*reportBatch)
+	}
+	batch, ok := e.batches[event.TaskID]
+	if !ok {
+		batch = &reportBatch{due: event.Timestamp.Add(interval)}
+		e.batches[event.TaskID] = batch
+	}
+	batch.events = append(batch.events, event)
+	
+	if t.MonitorOutcomeOnly.Discloses(event.EventType) || !event.Timestamp.Before(batch.due) {
+		delete(e.batches, event.TaskID)
+		return batch.events
+	}
+	return nil
+}
+
+// FlushReports discloses any batched events for a task now, regardless of
+// whether its reporting interval has elapsed.
+func (e *Engine) FlushReports(taskID string) error {
+	e.reportMu.Lock()
+	batch := e.batches[taskID]
+	delete(e.batches, taskID)
+	e.reportMu.Unlock()
+	
+	if batch == nil {
+		return nil
+	}
+	return e.discloseEvents(batch.events)
+}
+
+// FlushDueReports discloses every periodic batch whose interval has elapsed.
+func (e *Engine) FlushDueReports() {
+	now := time.Now()
+	e.reportMu.Lock()
+	var due []string
+	for id, batch := range e.batches {
+		if !now.Before(batch.due) {
+			due = append(due, id)
+		}
+	}
+	e.reportMu.Unlock()
+	
+	for _, id := range due {
+		if err := e.FlushReports(id); err != nil {
+			log.Printf("Flush periodic report for %s: %v", id, err)
+		}
+	}
+}
+
+// StartPeriodicReporting runs FlushDueReports every interval until stop is
+// called, so quiet periodic tasks still report on schedule.
+func (e *Engine) StartPeriodicReporting(interval time.Duration) (stop func()) {
+	return runEvery(interval, e.FlushDueReports)
+}
+
+// discloseEvents persists, audits and publishes events in order. Events left
+// undisclosed by a failure go back to the front of their batch.
+func (e *Engine) discloseEvents(events []t.MonitorEvent) error {
+	for i, event := range events {
+		if err := e.discloseEvent(event); err != nil {
+			e.requeueReports(events[i:])
+			return err
+		}
+	}
+	return nil
+}
+
+// requeueReports puts events back at the front of their task's batch, due at
+// once so the next flush retries them.
+func (e *Engine) requeueReports(events []t.MonitorEvent) {
+	e.reportMu.Lock()
+	defer e.reportMu.Unlock()
+	
+	if e.batches == nil {
+		e.batches = make(map[string]*reportBatch)
+	}
+	taskID := events[0].TaskID
+	batch, ok := e.batches[taskID]
+	if !ok {
+		batch = &reportBatch{}
+		e.batches[taskID] = batch
+	}
+	batch.events = append(append([]t.MonitorEvent{}, events...), batch.events...)
+	batch.due = time.Now()
+}
+
+// discloseEvent redacts an event, writes it to the shared audit log and the
+// task's monitoring channel, then rolls its milestone up to the parent.
+func (e *Engine) discloseEvent(event t.MonitorEvent) error {
+	event, err := e.redactForDisclosure(event)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	
+	// Persist event to audit log
+	if err := e.recordDomainEvent(t.DomainMonitorRecorded, event.TaskID, event); err != nil {
+		return err
+	}
+	eventKey := fmt.Sprintf("%s_%s", event.EventID, event.Timestamp.Format(time.RFC3339Nano))
+	if err := e.storeData(DomainMonitoring, event.TaskID, eventKey, body); err != nil {
+		return err
+	}
+	if err := e.storeData(DomainMonitoring, event.TaskID, "latest", body); err != nil {
+		return err
+	}
+	if err := e.appendAudit(event.TaskID, AuditMonitorEvent, event.EventID, body); err != nil {
+		return fmt.Errorf("audit event %s: %w", event.EventID, err)
+	}
+	
+	// Publish to monitoring channel
+	channelName := fmt.Sprintf("monitor_%s", event.TaskID)
+	rdid, _ := nc.RelationRetrieve(e.Server, channelName, e.Token)
+	if rdid != "" {
+		span := e.clientSpan(event.TaskID, "natsclient.SecureChannelPublish", attribute.String("nats.channel", channelName))
+		start := time.Now()
+		err := nc.SecureChannelPublish(body, e.Server, channelName, e.Token, rdid, 86400)
+		e.Metrics.observeNATS("publish", start, 0, false)
+		endClientSpan(span, err)
+	}
+	
+	// Child milestones move the parent forward
+	switch event.EventType {
+	case t.EventProgressUpdate, t.EventCheckpoint, t.EventTaskCompleted, t.EventTaskFailed:
+		if task, err := e.GetTask(event.TaskID); err == nil && task.ParentTaskID != "" {
+			if _, err := e.RollupParent(task.ParentTaskID); err != nil {
+				log.Printf("Roll up parent %s: %v", task.ParentTaskID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// receiveEvent handles an event observed on a monitoring channel: it feeds the
+// aggregator and flags events the task's mode should have withheld.
+func (e *Engine) receiveEvent(event t.MonitorEvent) {
+	e.observeEvent(event)
+	
+	// Outcomes are disclosed under every mode; skip the lookups for them
+	if t.MonitorOutcomeOnly.Discloses(event.EventType) {
+		return
+	}
+	mode, _ := e.monitoringTerms(event.TaskID)
+	if mode.Discloses(event.EventType) {
+		return
+	}
+	e.flagViolation(t.MonitoringViolation{
+		TaskID:  event.TaskID,
+		AgentID: event.AgentID,
+		Mode:    mode,
+		Kind:    "undisclosable_event",
+		EventID: event.EventID,
+		Detail:  fmt.Sprintf("%s event published under %s monitoring", event.EventType, mode),
+	})
+}
+
+// CheckReporting flags in-flight continuous and periodic tasks whose delegatee
+// has been silent for more than MissedReportGrace reporting intervals. Each
+// silence is flagged once; the next event clears it.
+func (e *Engine) CheckReporting() {
+	now := time.Now()
+	e.liveMu.RLock()
+	lastAt := make(map[string]time.Time, len(e.live))
+	agents := make(map[string]string, len(e.live))
+	for id, lt := range e.live {
+		lastAt[id] = lt.lastAt
+		agents[id] = lt.agentID
+	}
+	e.liveMu.RUnlock()
+	
+	for id, last := range lastAt {
+		task, err := e.GetTask(id)
+		switch {
+		case isNotFound(err):
+			e.forgetLive(id)
+			continue
+		case err != nil:
+			continue
+		case isTerminal(task.Status):
+			e.forgetLive(id) // Finished elsewhere; stop tracking it
+			continue
+		case task.Status == t.TaskCompleted || task.Status == t.TaskPaused:
+			continue // Paused delegatees are told to hold; their silence is expected
+		}
+		mode, interval := e.monitoringTerms(id)
+		if interval <= 0 || (mode != t.MonitorContinuous && mode != t.MonitorPeriodic) {
+			continue
+		}
+		if now.Sub(last) <= MissedReportGrace*interval {
+			continue
+		}
+		
+		e.reportMu.Lock()
+		if e.silent == nil {
+			e.silent = make(map[string]time.Time)
+		}
+		flagged := e.silent[id].Equal(last)
+		e.silent[id] = last
+		e.reportMu.Unlock()
+		if flagged {
+			continue
+		}
+		
+		e.flagViolation(t.MonitoringViolation{
+			TaskID:  id,
+			AgentID: agents[id],
+			Mode:    mode,
+			Kind:    "missed_report",
+			Detail: fmt.Sprintf("no report for %s under %s monitoring every %s",
+				now.Sub(last).Round(time.Second), mode, interval),
+		})
+	}
+}
+
+// StartReportingChecks runs CheckReporting every interval until stop is called.
+func (e *Engine) StartReportingChecks(interval time.Duration) (stop func()) {
+	return runEvery(interval, e.CheckReporting)
+}
+
+// GetMonitoringViolations returns the violations flagged on a task.
+func (e *Engine) GetMonitoringViolations(taskID string) ([]t.MonitoringViolation, error) {
+	data, err := e.retrieveData(DomainMonitoring, taskID, "violations")
+	if err != nil {
+		return nil, err
+	}
+	var violations []t.MonitoringViolation
+	if err := json.Unmarshal(data, &violations); err != nil {
+		return nil, fmt.Errorf("unmarshal violations: %w", err)
+	}
+	return violations, nil
+}
+
+// flagViolation records a violation and raises a non-urgent trigger so the
+// delegator's response cycle sees it.
+func (e *Engine) flagViolation(v t.MonitoringViolation) {
+	v.DetectedAt = time.Now()
+	log.Printf("MONITORING VIOLATION [%s] on task %s by %s: %s", v.Kind, v.TaskID, v.AgentID, v.Detail)
+	if err := e.appendList(DomainMonitoring, v.TaskID, "violations", v); err != nil {
+		log.Printf("Record monitoring violation on %s: %v", v.TaskID, err)
+	}
+	
+	err := e.RaiseTrigger(t.AdaptiveTrigger{
+		TriggerID:   fmt.Sprintf("monitoring_%s_%s_%d", v.Kind, v.TaskID, v.DetectedAt.UnixNano()),
+		TaskID:      v.TaskID,
+		Type:        t.TriggerIntMonitorBreach,
+		AgentID:     v.AgentID,
+		Description: v.Detail,
+	})
+	if err != nil {
+		log.Printf("Raise monitoring trigger on %s: %v", v.TaskID, err)
+	}
+}
@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// PARENT ROLL-UP (Sections 4.1, 4.5)
+// A decomposed task advances from its children: progress and spend come from
+// each child's latest monitoring event, outcomes from verification, and the
+// parent's CompletionPolicy decides when it is completed, verified or failed.
+// A parent that was itself delegated only rolls up to completed: its own
+// delegator still verifies it, which drives reputation and settlement.
+// Each roll-up is stored under Tasks/{id}/rollup and emitted as a parent event.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// RollupParent recomputes a parent task from its children, advances its status
+// if the completion policy is met, and emits a parent-level monitoring event.
+func (e *Engine) RollupParent(parentID string) (*t.TaskRollup, error) {
+	parent, err := e.GetTask(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("get parent task: %w", err)
+	}
+	if len(parent.SubTaskIDs) == 0 {
+		return nil, fmt.Errorf("task %s has no children to roll up", parentID)
+	}
+	
+	children := make([]t.TaskSpec, 0, len(parent.SubTaskIDs))
+	latest := make(map[string]*t.MonitorEvent, len(parent.SubTaskIDs))
+	for _, id := range parent.SubTaskIDs {
+		child, err := e.GetTask(id)
+		if err != nil {
+			return nil, fmt.Errorf("get child %s: %w", id, err)
+		}
+		children = append(children, *child)
+		if event, err := e.latestFigures(id); err == nil {
+			latest[id] = event
+		}
+	}
+	
+	rollup := computeRollup(parent, children, latest)
+	body, err := json.Marshal(rollup)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.storeData(DomainTasks, parentID, "rollup", body); err != nil {
+		return nil, err
+	}
+	
+	eventType := t.EventProgressUpdate
+	if rollup.Status != parent.Status && !isTerminal(parent.Status) {
+		switch rollup.Status {
+		case t.TaskVerified, t.TaskCompleted:
+			now := time.Now()
+			parent.CompletedAt = &now
+			eventType = t.EventTaskCompleted
+		case t.TaskFailed:
+			eventType = t.EventTaskFailed
+		}
+		log.Printf("Task %s rolled up %s → %s (%d/%d verified, %d failed)",
+			parentID, parent.Status, rollup.Status, rollup.Verified, rollup.Children, rollup.Failed)
+		parent.Status = rollup.Status
+		if err := e.UpdateTask(*parent); err != nil {
+			return nil, err
+		}
+	}
+	
+	severity := t.CriticalityLow
+	if eventType == t.EventTaskFailed {
+		severity = parent.Criticality
+	}
+	err = e.EmitMonitorEvent(t.MonitorEvent{
+		EventID:     fmt.Sprintf("rollup_%s_%d", parentID, rollup.ComputedAt.UnixNano()),
+		TaskID:      parentID,
+		AgentID:     e.SelfID,
+		EventType:   eventType,
+		Severity:    severity,
+		Progress:    rollup.Progress,
+		ResourceUse: rollup.Spend,
+		Message: fmt.Sprintf("roll-up: %d/%d verified, %d completed, %d failed, %d in flight",
+			rollup.Verified, rollup.Children, rollup.Completed, rollup.Failed, rollup.InFlight),
+	})
+	if err != nil {
+		return rollup, fmt.Errorf("emit parent event: %w", err)
+	}
+	return rollup, nil
+}
+
+// GetRollup retrieves the latest roll-up computed for a parent task.
+func (e *Engine) GetRollup(taskID string) (*t.TaskRollup, error) {
+	data, err := e.retrieveData(DomainTasks, taskID, "rollup")
+	if err != nil {
+		return nil, err
+	}
+	var rollup t.TaskRollup
+	if err := json.Unmarshal(data, &rollup); err != nil {
+		return nil, fmt.Errorf("unmarshal rollup: %w", err)
+	}
+	return &rollup, nil
+}
+
+// computeRollup aggregates children and applies the parent's completion policy.
+// With no policy every child must verify. A failed child counts against the
+// policy only while it stays failed; one being retried is back in flight.
+func computeRollup(parent *t.TaskSpec, children []t.TaskSpec, latest map[string]*t.MonitorEvent) *t.TaskRollup {
+	r := &t.TaskRollup{
+		TaskID:     parent.TaskID,
+		Children:   len(children),
+		Status:     parent.Status,
+		ComputedAt: time.Now(),
+	}
+	
+	var weighted, totalWeight float64
+	for _, child := range children {
+		progress := 0.0
+		if ev := latest[child.TaskID]; ev != nil {
+			if !ev.Withholds(FieldProgress) {
+				progress = ev.Progress
+			}
+			if !ev.Withholds(FieldResourceUse) {
+				r.Spend += ev.ResourceUse
+			}
+		}
+		
+		switch child.Status {
+		case t.TaskVerified:
+			r.Verified++
+			progress = 1.0
+		case t.TaskCompleted, t.TaskVerifying:
+			r.Completed++
+			progress = 1.0
+		case t.TaskFailed, t.TaskCancelled:
+			r.Failed++
+		default:
+			r.InFlight++
+		}
+		
+		// Longer children count for more of the parent's progress
+		weight := float64(child.EstimatedDuration)
+		if weight <= 0 {
+			weight = 1
+		}
+		weighted += progress * weight
+		totalWeight += weight
+	}
+	if totalWeight > 0 {
+		r.Progress = weighted / totalWeight
+	}
+	
+	r.Required = r.Children
+	if p := parent.CompletionPolicy; p != nil && p.Mode == t.CompletionQuorum && p.Quorum > 0 {
+		r.Required = int(math.Ceil(p.Quorum * float64(r.Children)))
+		if r.Required > r.Children {
+			r.Required = r.Children
+		}
+	}
+	
+	switch {
+	case r.Verified >= r.Required && parent.DelegateeID != "":
+		r.Status = t.TaskCompleted // Awaiting the parent's own verification
+	case r.Verified >= r.Required:
+		r.Status = t.TaskVerified
+	case r.Children-r.Failed < r.Required:
+		r.Status = t.TaskFailed // Policy can no longer be met
+	case r.Verified+r.Completed >= r.Required:
+		r.Status = t.TaskCompleted
+	}
+	return r
+}
+
+// isTerminal reports whether a task status is final for roll-up purposes.
+func isTerminal(status t.TaskStatus) bool {
+	switch status {
+	case t.TaskVerified, t.TaskFailed, t.TaskCancelled:
+		return true
+	}
+	return false
+}
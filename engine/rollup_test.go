@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"testing"
+	"time"
+	
+	"github.com/dataparency-dev/AI-delegation/types"
+)
+
+func TestComputeRollup(t *testing.T) {
+	child := func(id string, status types.TaskStatus, duration int64) types.TaskSpec {
+		return types.TaskSpec{TaskID: id, Status: status, EstimatedDuration: duration}
+	}
+	quorum := func(q float64) *types.CompletionPolicy {
+		return &types.CompletionPolicy{Mode: types.CompletionQuorum, Quorum: q}
+	}
+	tests := []struct {
+		name      string
+		delegatee string
+		policy    *types.CompletionPolicy
+		children  []types.TaskSpec
+		latest    map[string]*types.MonitorEvent
+		want      types.TaskRollup
+	}{
+		{
+			name:     "all verified",
+			children: []types.TaskSpec{child("a", types.TaskVerified, 0), child("b", types.TaskVerified, 0)},
+			want:     types.TaskRollup{Verified: 2, Required: 2, Progress: 1, Status: types.TaskVerified},
+		},
+		{
+			name:      "all verified under a delegated parent",
+			delegatee: "agent-1",
+			children:  []types.TaskSpec{child("a", types.TaskVerified, 0)},
+			want:      types.TaskRollup{Verified: 1, Required: 1, Progress: 1, Status: types.TaskCompleted},
+		},
+		{
+			name:     "all completed, awaiting verification",
+			children: []types.TaskSpec{child("a", types.TaskCompleted, 0), child("b", types.TaskVerifying, 0)},
+			want:     types.TaskRollup{Completed: 2, Required: 2, Progress: 1, Status: types.TaskCompleted},
+		},
+		{
+			name:     "duration-weighted progress and spend",
+			children: []types.TaskSpec{child("a", types.TaskVerified, 1), child("b", types.TaskInProgress, 3)},
+			latest: map[string]*types.MonitorEvent{
+				"a": {ResourceUse: 10},
+				"b": {Progress: 0.5, ResourceUse: 25},
+			},
+			want: types.TaskRollup{Verified: 1, InFlight: 1, Required: 2, Progress: 0.625, Spend: 35, Status: types.TaskDecomposed},
+		},
+		{
+			name:     "withheld figures are not read as zero progress or spend",
+			children: []types.TaskSpec{child("a", types.TaskInProgress, 0)},
+			latest: map[string]*types.MonitorEvent{
+				"a": {Progress: 0.9, ResourceUse: 30, Redacted: []string{FieldProgress, FieldResourceUse}},
+			},
+			want: types.TaskRollup{InFlight: 1, Required: 1, Status: types.TaskDecomposed},
+		},
+		{
+			name:     "a failed child fails an all-children policy",
+			children: []types.TaskSpec{child("a", types.TaskVerified, 0), child("b", types.TaskFailed, 0)},
+			want:     types.TaskRollup{Verified: 1, Failed: 1, Required: 2, Progress: 0.5, Status: types.TaskFailed},
+		},
+		{
+			name:     "a child being retried is in flight",
+			children: []types.TaskSpec{child("a", types.TaskVerified, 0), child("b", types.TaskReAllocating, 0)},
+			want:     types.TaskRollup{Verified: 1, InFlight: 1, Required: 2, Progress: 0.5, Status: types.TaskDecomposed},
+		},
+		{
+			name:   "quorum met despite a failure",
+			policy: quorum(0.5),
+			children: []types.TaskSpec{
+				child("a", types.TaskVerified, 0), child("b", types.TaskVerified, 0),
+				child("c", types.TaskCancelled, 0), child("d", types.TaskInProgress, 0),
+			},
+			want: types.TaskRollup{Verified: 2, Failed: 1, InFlight: 1, Required: 2, Progress: 0.5, Status: types.TaskVerified},
+		},
+		{
+			name:   "quorum out of reach",
+			policy: quorum(0.75),
+			children: []types.TaskSpec{
+				child("a", types.TaskVerified, 0), child("b", types.TaskFailed, 0),
+				child("c", types.TaskFailed, 0), child("d", types.TaskInProgress, 0),
+			},
+			want: types.TaskRollup{Verified: 1, Failed: 2, InFlight: 1, Required: 3, Progress: 0.25, Status: types.TaskFailed},
+		},
+		{
+			name:     "quorum above the whole needs every child",
+			policy:   quorum(1.5),
+			children: []types.TaskSpec{child("a", types.TaskVerified, 0), child("b", types.TaskInProgress, 0)},
+			want:     types.TaskRollup{Verified: 1, InFlight: 1, Required: 2, Progress: 0.5, Status: types.TaskDecomposed},
+		},
+		{
+			name: "no children",
+			want: types.TaskRollup{Status: types.TaskVerified},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parent := &types.TaskSpec{
+				TaskID:           "p",
+				Status:           types.TaskDecomposed,
+				DelegateeID:      tc.delegatee,
+				CompletionPolicy: tc.policy,
+			}
+			got := *computeRollup(parent, tc.children, tc.latest)
+			got.ComputedAt = time.Time{}
+			want := tc.want
+			want.TaskID = "p"
+			want.Children = len(tc.children)
+			if got != want {
+				t.Errorf("computeRollup() =\n  %+v\nwant\n  %+v", got, want)
+			}
+		})
+	}
+}
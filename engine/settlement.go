@@ -0,0 +1,238 @@
+package engine
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strconv"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// SETTLEMENT & INVOICING (Section 4.2)
+// Verified work is priced from the accepted bid, the final monitored resource
+// use, late-delivery penalties and escrow. Records are stored per contract and
+// indexed per delegator under Contracts/{delegator_id}/settlements.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// SettleContract computes and stores the settlement for a contract whose task
+// has been verified, and marks the contract completed. Settling again returns
+// the stored record, so a repeated verification never bills twice.
+func (e *Engine) SettleContract(contractID string) (*t.SettlementRecord, error) {
+	contract, err := e.GetContract(contractID)
+	if err != nil {
+		return nil, fmt.Errorf("get contract: %w", err)
+	}
+	if contract.Status == t.ContractCompleted {
+		return e.GetSettlement(contractID)
+	}
+	
+	// A record stored by an attempt that failed before completing the contract
+	// is reused rather than recomputed
+	var stored t.SettlementRecord
+	found, err := e.retrieveJSON(DomainContracts, contract.ContractID, "settlement", &stored)
+	if err != nil {
+		return nil, fmt.Errorf("get settlement: %w", err)
+	}
+	if found {
+		return e.completeSettlement(contract, &stored)
+	}
+	
+	task, err := e.GetTask(contract.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("get task: %w", err)
+	}
+	if task.Status != t.TaskVerified {
+		return nil, fmt.Errorf("task %s is %s; only verified tasks are settled", task.TaskID, task.Status)
+	}
+	
+	// Withheld usage bills the agreed price rather than nothing
+	var resourceUse float64
+	if event, err := e.latestFigures(task.TaskID); err == nil && !event.Withholds(FieldResourceUse) {
+		resourceUse = event.ResourceUse
+	}
+	
+	completedAt := time.Now()
+	if task.CompletedAt != nil {
+		completedAt = *task.CompletedAt
+	}
+	deadline := contract.Terms.Deadline
+	if deadline.IsZero() && task.Deadline != nil {
+		deadline = *task.Deadline
+	}
+	
+	record := computeSettlement(contract, resourceUse, deadline, completedAt)
+	body, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.storeData(DomainContracts, contract.ContractID, "settlement", body); err != nil {
+		return nil, err
+	}
+	return e.completeSettlement(contract, record)
+}
+
+// completeSettlement adds a stored settlement to the delegator's invoice index,
+// unless it is already there, and marks the contract completed.
+func (e *Engine) completeSettlement(contract *t.DelegationContract, record *t.SettlementRecord) (*t.SettlementRecord, error) {
+	var indexed []t.SettlementRecord
+	if _, err := e.retrieveJSON(DomainContracts, contract.DelegatorID, "settlements", &indexed); err != nil {
+		return nil, fmt.Errorf("read settlement index: %w", err)
+	}
+	listed := false
+	for _, rec := range indexed {
+		listed = listed || rec.SettlementID == record.SettlementID
+	}
+	if !listed {
+		if err := e.appendList(DomainContracts, contract.DelegatorID, "settlements", record); err != nil {
+			return nil, fmt.Errorf("index settlement: %w", err)
+		}
+	}
+	
+	contract.Status = t.ContractCompleted
+	if err := e.storeContract(contract); err != nil {
+		return nil, err
+	}
+	
+	log.Printf("Contract %s settled: due %.2f (penalty %.2f, escrow applied %.2f, balance %.2f)",
+		contract.ContractID, record.AmountDue, record.Penalty, record.EscrowApplied, record.BalanceDue)
+	return record, nil
+}
+
+// computeSettlement prices a contract. Usage is billed up to the contract's
+// MaxCost; with no usage reported the accepted bid price is billed. Lateness is
+// penalised at PenaltyRate per started hour, never exceeding the billable amount.
+func computeSettlement(contract *t.DelegationContract, resourceUse float64, deadline, completedAt time.Time) *t.SettlementRecord {
+	var agreed float64
+	if contract.AcceptedBid != nil {
+		agreed = contract.AcceptedBid.EstimatedCost
+	}
+	
+	billable := resourceUse
+	if billable <= 0 {
+		billable = agreed
+	}
+	if contract.Terms.MaxCost > 0 && billable > contract.Terms.MaxCost {
+		billable = contract.Terms.MaxCost
+	}
+	
+	var lateSeconds int64
+	var penalty float64
+	if !deadline.IsZero() && completedAt.After(deadline) {
+		late := completedAt.Sub(deadline)
+		lateSeconds = int64(late.Seconds())
+		penalty = math.Ceil(late.Hours()) * contract.Terms.PenaltyRate
+		if penalty > billable {
+			penalty = billable
+		}
+	}
+	
+	due := billable - penalty
+	escrowApplied := math.Min(contract.Terms.EscrowAmount, due)
+	
+	return &t.SettlementRecord{
+		SettlementID:  fmt.Sprintf("settle_%s", contract.ContractID),
+		ContractID:    contract.ContractID,
+		TaskID:        contract.TaskID,
+		DelegatorID:   contract.DelegatorID,
+		DelegateeID:   contract.DelegateeID,
+		AgreedPrice:   agreed,
+		ResourceUse:   resourceUse,
+		Billable:      billable,
+		LateSeconds:   lateSeconds,
+		Penalty:       penalty,
+		AmountDue:     due,
+		EscrowHeld:    contract.Terms.EscrowAmount,
+		EscrowApplied: escrowApplied,
+		EscrowRefund:  contract.Terms.EscrowAmount - escrowApplied,
+		BalanceDue:    due - escrowApplied,
+		CompletedAt:   completedAt,
+		SettledAt:     time.Now(),
+	}
+}
+
+// GetSettlement retrieves the settlement record for a contract.
+func (e *Engine) GetSettlement(contractID string) (*t.SettlementRecord, error) {
+	data, err := e.retrieveData(DomainContracts, contractID, "settlement")
+	if err != nil {
+		return nil, err
+	}
+	var record t.SettlementRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("unmarshal settlement: %w", err)
+	}
+	return &record, nil
+}
+
+// GenerateInvoice collects a delegator's settlements made in [from, to).
+func (e *Engine) GenerateInvoice(delegatorID string, from, to time.Time) (*t.Invoice, error) {
+	// A delegator with nothing settled yet gets an empty invoice
+	var records []t.SettlementRecord
+	if _, err := e.retrieveJSON(DomainContracts, delegatorID, "settlements", &records); err != nil {
+		return nil, fmt.Errorf("settlements of %s: %w", delegatorID, err)
+	}
+	
+	invoice := &t.Invoice{
+		InvoiceID:   fmt.Sprintf("inv_%s_%s", delegatorID, from.Format("20060102")),
+		DelegatorID: delegatorID,
+		PeriodStart: from,
+		PeriodEnd:   to,
+		IssuedAt:    time.Now(),
+	}
+	seen := make(map[string]bool, len(records))
+	for _, rec := range records {
+		if seen[rec.SettlementID] || rec.SettledAt.Before(from) || !rec.SettledAt.Before(to) {
+			continue
+		}
+		seen[rec.SettlementID] = true
+		invoice.Lines = append(invoice.Lines, rec)
+		invoice.TotalDue += rec.AmountDue
+		invoice.TotalEscrow += rec.EscrowApplied
+		invoice.TotalBalance += rec.BalanceDue
+	}
+	return invoice, nil
+}
+
+// ExportInvoice writes an invoice as "json" or "csv" (one row per settlement).
+func ExportInvoice(w io.Writer, invoice *t.Invoice, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(invoice)
+	case "csv":
+		return exportInvoiceCSV(w, invoice)
+	default:
+		return fmt.Errorf("unsupported invoice format %q (want json or csv)", format)
+	}
+}
+
+func exportInvoiceCSV(w io.Writer, invoice *t.Invoice) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"invoice_id", "contract_id", "task_id", "delegatee_id", "settled_at",
+		"agreed_price", "resource_use", "billable", "late_seconds", "penalty",
+		"amount_due", "escrow_applied", "balance_due",
+	})
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	for _, rec := range invoice.Lines {
+		cw.Write([]string{
+			invoice.InvoiceID, rec.ContractID, rec.TaskID, rec.DelegateeID,
+			rec.SettledAt.Format(time.RFC3339),
+			money(rec.AgreedPrice), money(rec.ResourceUse), money(rec.Billable),
+			strconv.FormatInt(rec.LateSeconds, 10), money(rec.Penalty),
+			money(rec.AmountDue), money(rec.EscrowApplied), money(rec.BalanceDue),
+		})
+	}
+	cw.Write([]string{
+		invoice.InvoiceID, "TOTAL", "", "", "", "", "", "", "", "",
+		money(invoice.TotalDue), money(invoice.TotalEscrow), money(invoice.TotalBalance),
+	})
+	cw.Flush()
+	return cw.Error()
+}
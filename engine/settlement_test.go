@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"testing"
+	"time"
+	
+	"github.com/dataparency-dev/AI-delegation/types"
+)
+
+func TestComputeSettlement(t *testing.T) {
+	deadline := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	contract := func(escrow float64) *types.DelegationContract {
+		return &types.DelegationContract{
+			ContractID:  "c1",
+			TaskID:      "t1",
+			AcceptedBid: &types.Bid{EstimatedCost: 50},
+			Terms:       types.ContractTerms{MaxCost: 80, PenaltyRate: 10, EscrowAmount: escrow},
+		}
+	}
+	tests := []struct {
+		name        string
+		contract    *types.DelegationContract
+		use         float64
+		deadline    time.Time
+		completedAt time.Time
+		want        types.SettlementRecord
+	}{
+		{"usage on time", contract(20), 60, deadline, deadline.Add(-time.Hour),
+			types.SettlementRecord{AgreedPrice: 50, Billable: 60, AmountDue: 60, EscrowApplied: 20, BalanceDue: 40}},
+		{"no usage bills the bid", contract(20), 0, deadline, deadline,
+			types.SettlementRecord{AgreedPrice: 50, Billable: 50, AmountDue: 50, EscrowApplied: 20, BalanceDue: 30}},
+		{"usage capped at MaxCost", contract(20), 120, deadline, deadline,
+			types.SettlementRecord{AgreedPrice: 50, Billable: 80, AmountDue: 80, EscrowApplied: 20, BalanceDue: 60}},
+		{"late by a started second hour", contract(20), 60, deadline, deadline.Add(90 * time.Minute),
+			types.SettlementRecord{AgreedPrice: 50, Billable: 60, LateSeconds: 5400, Penalty: 20, AmountDue: 40, EscrowApplied: 20, BalanceDue: 20}},
+		{"penalty capped at the billable amount", contract(20), 60, deadline, deadline.Add(10 * time.Hour),
+			types.SettlementRecord{AgreedPrice: 50, Billable: 60, LateSeconds: 36000, Penalty: 60, EscrowRefund: 20}},
+		{"no deadline", contract(20), 60, time.Time{}, deadline.Add(10 * time.Hour),
+			types.SettlementRecord{AgreedPrice: 50, Billable: 60, AmountDue: 60, EscrowApplied: 20, BalanceDue: 40}},
+		{"escrow above the amount due", contract(20), 10, deadline, deadline,
+			types.SettlementRecord{AgreedPrice: 50, Billable: 10, AmountDue: 10, EscrowApplied: 10, EscrowRefund: 10}},
+		{"no accepted bid", &types.DelegationContract{ContractID: "c1"}, 0, deadline, deadline,
+			types.SettlementRecord{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := computeSettlement(tc.contract, tc.use, tc.deadline, tc.completedAt)
+			want := tc.want
+			want.SettlementID = "settle_c1"
+			want.ContractID = tc.contract.ContractID
+			want.TaskID = tc.contract.TaskID
+			want.ResourceUse = tc.use
+			want.EscrowHeld = tc.contract.Terms.EscrowAmount
+			want.CompletedAt = tc.completedAt
+			want.SettledAt = got.SettledAt
+			if *got != want {
+				t.Errorf("computeSettlement() =\n  %+v\nwant\n  %+v", *got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DefaultMaxDelegationDepth bounds how many delegatee hops a task tree may grow.
+const DefaultMaxDelegationDepth = 3
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// RECURSIVE SUB-DELEGATION (Sections 4.1, 4.7)
+// A delegatee may decompose and re-delegate its task only as far as the task's
+// AutonomyLevel allows. Children inherit budget and deadline bounds from the
+// parent, must pass the same consistency checks as a decomposition, and are
+// linked into the parent's SubTaskIDs so the original delegator can still see
+// the whole tree.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// SubDelegate is called by a delegatee to split its assigned task into child
+// tasks it will delegate onward. The engine's SelfID must be the parent's delegatee.
+func (e *Engine) SubDelegate(parentID string, children []t.TaskSpec) (_ *t.TaskSpec, err error) {
+	parent, err := e.GetTask(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("get parent task: %w", err)
+	}
+	ctx, done := e.traceTaskFrom(parent.TraceContext, parentID, "engine.SubDelegate",
+		attribute.Int("delegation.sub_tasks", len(children)))
+	defer done(&err)
+	if parent.DelegateeID != e.SelfID {
+		return nil, fmt.Errorf("%s is not the delegatee of task %s", e.SelfID, parentID)
+	}
+	if isTerminal(parent.Status) {
+		return nil, fmt.Errorf("task %s is %s; sub-delegation not permitted", parentID, parent.Status)
+	}
+	if len(children) == 0 {
+		return nil, fmt.Errorf("no child tasks given for %s", parentID)
+	}
+	allocated, err := e.allocatedBudget(parent)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.checkSubDelegation(parent, children, allocated); err != nil {
+		return nil, err
+	}
+	if err := ValidateDependencies(children); err != nil {
+		return nil, err
+	}
+	
+	if err := inheritBounds(parent, children, allocated); err != nil {
+		return nil, err
+	}
+	if report := subDelegationReport(parent, children); len(report.Violations) > 0 {
+		return nil, &DecompositionError{Report: report}
+	}
+	
+	for i := range children {
+		child := &children[i]
+		child.ParentTaskID = parentID
+		child.DelegatorID = e.SelfID
+		child.DelegateeID = ""
+		child.DelegationDepth = parent.DelegationDepth + 1
+		if child.AutonomyLevel == "" {
+			child.AutonomyLevel = t.AutonomyAtomic
+		}
+		child.TraceContext = injectTrace(ctx)
+		if err := e.CreateTask(*child); err != nil {
+			return nil, fmt.Errorf("create child task %s: %w", child.TaskID, err)
+		}
+		parent.SubTaskIDs = append(parent.SubTaskIDs, child.TaskID)
+	}
+	
+	parent.IsLeaf = false
+	if err := e.UpdateTask(*parent); err != nil {
+		return nil, fmt.Errorf("update parent task: %w", err)
+	}
+	
+	// Keep the original delegator informed of the new branch
+	channelName := agentChannelName(parentID, parent.DelegatorID, e.SelfID)
+	if err := e.publishAgentMessage(channelName, parentID, t.MsgSubDelegated, children); err != nil {
+		log.Printf("Notify %s of sub-delegation on %s: %v", parent.DelegatorID, parentID, err)
+	}
+	
+	log.Printf("Task %s sub-delegated by %s into %d child tasks (depth %d)",
+		parentID, e.SelfID, len(children), parent.DelegationDepth+1)
+	return parent, nil
+}
+
+// allocatedBudget sums the budgets already handed to the parent's existing
+// children, so repeated sub-delegation cannot exceed the parent's budget.
+// Cancelled children release their share.
+func (e *Engine) allocatedBudget(parent *t.TaskSpec) (float64, error) {
+	var allocated float64
+	for _, childID := range parent.SubTaskIDs {
+		child, err := e.GetTask(childID)
+		if err != nil {
+			return 0, fmt.Errorf("get existing child %s: %w", childID, err)
+		}
+		if child.Status != t.TaskCancelled {
+			allocated += child.MaxBudget
+		}
+	}
+	return allocated, nil
+}
+
+// checkSubDelegation enforces the parent's autonomy level, the depth and chain
+// length limits, and the budget and deadline ceilings. allocated is the budget
+// already held by the parent's existing children.
+func (e *Engine) checkSubDelegation(parent *t.TaskSpec, children []t.TaskSpec, allocated float64) error {
+	switch parent.AutonomyLevel {
+	case t.AutonomyBounded, t.AutonomyOpenEnd:
+	default:
+		return fmt.Errorf("task %s is %q; sub-delegation not permitted", parent.TaskID, parent.AutonomyLevel)
+	}
+	
+	maxDepth := e.MaxDelegationDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDelegationDepth
+	}
+	if parent.DelegationDepth+1 > maxDepth {
+		return fmt.Errorf("task %s is at delegation depth %d; limit is %d",
+			parent.TaskID, parent.DelegationDepth, maxDepth)
+	}
+	
+	maxChain := e.MaxChainLength
+	if maxChain <= 0 {
+		maxChain = DefaultMaxChainLength
+	}
+	if len(parent.DelegationChain)+1 > maxChain {
+		return fmt.Errorf("task %s already has a %d-link delegation chain; limit is %d",
+			parent.TaskID, len(parent.DelegationChain), maxChain)
+	}
+	
+	var total float64
+	for _, child := range children {
+		// Bounded autonomy may only hand out atomic work; open-ended may pass on
+		// at most its own level
+		if parent.AutonomyLevel == t.AutonomyBounded && child.AutonomyLevel.Rank() > t.AutonomyAtomic.Rank() {
+			return fmt.Errorf("child %s asks for %q under a bounded parent; only atomic is allowed",
+				child.TaskID, child.AutonomyLevel)
+		}
+		if child.AutonomyLevel.Rank() > parent.AutonomyLevel.Rank() {
+			return fmt.Errorf("child %s autonomy %q exceeds parent's %q",
+				child.TaskID, child.AutonomyLevel, parent.AutonomyLevel)
+		}
+		if parent.Deadline != nil && child.Deadline != nil && child.Deadline.After(*parent.Deadline) {
+			return fmt.Errorf("child %s deadline %s is after parent deadline %s",
+				child.TaskID, child.Deadline.Format("2006-01-02T15:04"), parent.Deadline.Format("2006-01-02T15:04"))
+		}
+		total += child.MaxBudget
+	}
+	if parent.MaxBudget > 0 && allocated+total > parent.MaxBudget {
+		return fmt.Errorf("child budgets total %.2f (%.2f already allocated), exceeding parent budget %.2f",
+			allocated+total, allocated, parent.MaxBudget)
+	}
+	return nil
+}
+
+// subDelegationReport runs the decomposition checks on children after they
+// have inherited the parent's bounds, so explicit permissions, budgets and
+// deadlines cannot exceed the parent's. Capability coverage is dropped: a
+// delegatee may keep part of the work and sub-delegate only the rest.
+func subDelegationReport(parent *t.TaskSpec, children []t.TaskSpec) *t.DecompositionReport {
+	report := checkDecomposition(parent, children)
+	kept := report.Violations[:0]
+	for _, v := range report.Violations {
+		if v.Check != t.CheckCapabilities {
+			kept = append(kept, v)
+		}
+	}
+	report.Violations = kept
+	return report
+}
+
+// inheritBounds fills unset child deadlines, permissions, criticality and
+// priority from the parent, and splits the parent's unallocated budget evenly
+// among children that set none. A zero budget means unlimited, so a budgeted
+// parent with nothing left to share is an error rather than a zero share.
+func inheritBounds(parent *t.TaskSpec, children []t.TaskSpec, allocated float64) error {
+	var unbudgeted int
+	for _, child := range children {
+		if child.MaxBudget > 0 {
+			allocated += child.MaxBudget
+		} else {
+			unbudgeted++
+		}
+	}
+	var share float64
+	if unbudgeted > 0 && parent.MaxBudget > 0 {
+		if parent.MaxBudget <= allocated {
+			return fmt.Errorf("parent %s budget %.2f is fully allocated; %d child tasks have no budget",
+				parent.TaskID, parent.MaxBudget, unbudgeted)
+		}
+		share = (parent.MaxBudget - allocated) / float64(unbudgeted)
+	}
+	
+	for i := range children {
+		child := &children[i]
+		if child.MaxBudget <= 0 {
+			child.MaxBudget = share
+		}
+		if child.Deadline == nil && parent.Deadline != nil {
+			deadline := *parent.Deadline
+			child.Deadline = &deadline
+		}
+		if len(child.Permissions) == 0 {
+			child.Permissions = parent.Permissions
+		}
+		if child.Criticality == "" {
+			child.Criticality = parent.Criticality
+		}
+		if child.Priority == 0 {
+			child.Priority = parent.Priority
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	
+	"github.com/dataparency-dev/AI-delegation/security"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// TASK TOKEN REGISTRY (Section 4.7)
+// DCTs minted for a task are registered so they can be revoked with it:
+//   Tasks/{id}/dct_{token_id} → DCT JSON
+//   Tasks/{id}/tokens         → []token_id
+// The registered copy is authoritative: a bearer's own copy never learns that
+// it was revoked, so access checks go through ValidateTaskToken.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// RegisterTaskToken records a DCT issued for work on a task.
+func (e *Engine) RegisterTaskToken(taskID string, dct *security.DCT) error {
+	if err := e.storeTaskToken(taskID, dct); err != nil {
+		return err
+	}
+	return e.appendList(DomainTasks, taskID, "tokens", dct.TokenID)
+}
+
+// GetTaskTokens returns every DCT registered for a task.
+func (e *Engine) GetTaskTokens(taskID string) ([]security.DCT, error) {
+	var ids []string
+	if _, err := e.retrieveJSON(DomainTasks, taskID, "tokens", &ids); err != nil {
+		return nil, fmt.Errorf("read token index for %s: %w", taskID, err)
+	}
+	
+	tokens := make([]security.DCT, 0, len(ids))
+	for _, id := range ids {
+		data, err := e.retrieveData(DomainTasks, taskID, "dct_"+id)
+		if err != nil {
+			return nil, fmt.Errorf("get token %s: %w", id, err)
+		}
+		var dct security.DCT
+		if err := json.Unmarshal(data, &dct); err != nil {
+			return nil, fmt.Errorf("unmarshal token %s: %w", id, err)
+		}
+		tokens = append(tokens, dct)
+	}
+	return tokens, nil
+}
+
+// RevokeTaskTokens marks every DCT registered for a task as revoked and
+// returns how many were newly revoked.
+func (e *Engine) RevokeTaskTokens(taskID string) (int, error) {
+	tokens, err := e.GetTaskTokens(taskID)
+	if err != nil {
+		return 0, err
+	}
+	revoked := 0
+	for i := range tokens {
+		if tokens[i].Revoked {
+			continue
+		}
+		tokens[i].Revoked = true
+		if err := e.storeTaskToken(taskID, &tokens[i]); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+	if revoked > 0 {
+		log.Printf("Revoked %d tokens for task %s", revoked, taskID)
+	}
+	return revoked, nil
+}
+
+// ValidateTaskToken checks a presented DCT against the copy registered for the
+// task: unregistered and revoked tokens are refused, and the registered caveats
+// and expiry decide the operation, so a bearer cannot loosen its own copy.
+func (e *Engine) ValidateTaskToken(taskID string, dct *security.DCT, operation, scope string) error {
+	var stored security.DCT
+	found, err := e.retrieveJSON(DomainTasks, taskID, "dct_"+dct.TokenID, &stored)
+	if err != nil {
+		return fmt.Errorf("get token %s: %w", dct.TokenID, err)
+	}
+	if !found {
+		return fmt.Errorf("token %s is not registered for task %s", dct.TokenID, taskID)
+	}
+	if stored.BearerID != dct.BearerID {
+		return fmt.Errorf("token %s is not held by %s", dct.TokenID, dct.BearerID)
+	}
+	return stored.ValidateAccess(operation, scope)
+}
+
+func (e *Engine) storeTaskToken(taskID string, dct *security.DCT) error {
+	body, err := json.Marshal(dct)
+	if err != nil {
+		return err
+	}
+	return e.storeData(DomainTasks, taskID, "dct_"+dct.TokenID, body)
+}
@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"context"
+	
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// DISTRIBUTED TRACING (Section 4.5 — transparency)
+// Engine operations on a task run inside an OpenTelemetry span parented on the
+// trace context stored with the task, so one trace follows a request through
+// decomposition, bidding, sub-delegation and verification on every engine.
+// Agent messages carry the sender's context. While a task span is open, the
+// natsclient calls made for that task are recorded as its children.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// TracerName identifies the engine's instrumentation scope.
+const TracerName = "github.com/dataparency-dev/AI-delegation/engine"
+
+var traceFormat = propagation.TraceContext{}
+
+// unsetProvider is the global provider before the application installs one;
+// it drops every span.
+var unsetProvider = otel.GetTracerProvider()
+
+func (e *Engine) tracer() trace.Tracer {
+	if e.Tracer != nil {
+		return e.Tracer
+	}
+	return otel.Tracer(TracerName)
+}
+
+// tracing reports whether spans can be recorded at all: false for a no-op
+// Tracer, or with no Tracer and no global provider installed.
+func (e *Engine) tracing() bool {
+	if e.Tracer != nil {
+		_, off := e.Tracer.(noop.Tracer)
+		return !off
+	}
+	provider := otel.GetTracerProvider()
+	if _, off := provider.(noop.TracerProvider); off {
+		return false
+	}
+	return provider != unsetProvider
+}
+
+// traceTask starts a span for an operation on a task, parented on the task's
+// open span or, failing that, on the trace context stored with the task. The
+// task is only read when a span could be recorded.
+func (e *Engine) traceTask(taskID, name string, attrs ...attribute.KeyValue) (ctx context.Context, done func(*error)) {
+	var carrier map[string]string
+	if e.tracing() && !trace.SpanContextFromContext(e.activeTrace(taskID)).IsValid() {
+		if task, err := e.GetTask(taskID); err == nil {
+			carrier = task.TraceContext
+		}
+	}
+	return e.traceTaskFrom(carrier, taskID, name, attrs...)
+}
+
+// traceTaskFrom starts a span for an operation on a task, parented on carrier
+// (a stored or received trace context) if it holds one, else on the task's
+// open span. The new span is the task's active span for natsclient calls
+// until done is called with the operation's error.
+func (e *Engine) traceTaskFrom(carrier map[string]string, taskID, name string, attrs ...attribute.KeyValue) (ctx context.Context, done func(*error)) {
+	parent := traceFormat.Extract(context.Background(), propagation.MapCarrier(carrier))
+	if !trace.SpanContextFromContext(parent).IsValid() {
+		parent = e.activeTrace(taskID)
+	}
+	attrs = append(attrs, attribute.String("delegation.task_id", taskID), attribute.String("delegation.agent_id", e.SelfID))
+	ctx, span := e.tracer().Start(parent, name, trace.WithAttributes(attrs...))
+	
+	e.traceMu.Lock()
+	if e.traces == nil {
+		e.traces = make(map[string][]context.Context)
+	}
+	e.traces[taskID] = append(e.traces[taskID], ctx)
+	e.traceMu.Unlock()
+	
+	return ctx, func(errp *error) {
+		e.traceMu.Lock()
+		stack := e.traces[taskID]
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i] == ctx {
+				stack = append(stack[:i], stack[i+1:]...)
+				break
+			}
+		}
+		if len(stack) == 0 {
+			delete(e.traces, taskID)
+		} else {
+			e.traces[taskID] = stack
+		}
+		e.traceMu.Unlock()
+		
+		if errp != nil && *errp != nil {
+			span.RecordError(*errp)
+			span.SetStatus(codes.Error, (*errp).Error())
+		}
+		span.End()
+	}
+}
+
+// activeTrace returns the innermost open span context for a task, or an
+// empty context when none is open.
+func (e *Engine) activeTrace(taskID string) context.Context {
+	e.traceMu.Lock()
+	defer e.traceMu.Unlock()
+	if stack := e.traces[taskID]; len(stack) > 0 {
+		return stack[len(stack)-1]
+	}
+	return context.Background()
+}
+
+// clientSpan starts a span for a natsclient call under the entity's active
+// task span. With no span open it returns a non-recording span, so storage
+// calls outside a traced operation do not start traces of their own.
+func (e *Engine) clientSpan(entity, name string, attrs ...attribute.KeyValue) trace.Span {
+	parent := e.activeTrace(entity)
+	if !trace.SpanContextFromContext(parent).IsValid() {
+		return trace.SpanFromContext(parent)
+	}
+	_, span := e.tracer().Start(parent, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return span
+}
+
+// endClientSpan marks a natsclient span failed if err is set and ends it.
+func endClientSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func storageAttrs(domain, entity, aspect string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("ddn.domain", domain),
+		attribute.String("ddn.entity", entity),
+		attribute.String("ddn.aspect", aspect),
+	}
+}
+
+// injectTrace serializes a span context for storage on a task or a message.
+func injectTrace(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	traceFormat.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
@@ -0,0 +1,200 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════════
+// TASK TREE (Sections 4.1, 4.5)
+// Walks SubTaskIDs from a root to give the delegator one view of the whole
+// delegation: who holds each node, under which contract, and how far along it
+// is. Trees export to Graphviz DOT and Mermaid for reviews and incident reports.
+// ═══════════════════════════════════════════════════════════════════════════════
+
+// GetTaskTree loads rootID and all of its descendants.
+func (e *Engine) GetTaskTree(rootID string) (*t.TaskTreeNode, error) {
+	return e.loadTreeNode(rootID, 0, make(map[string]bool))
+}
+
+func (e *Engine) loadTreeNode(taskID string, depth int, seen map[string]bool) (*t.TaskTreeNode, error) {
+	if seen[taskID] {
+		return nil, fmt.Errorf("task %s appears twice in the tree", taskID)
+	}
+	seen[taskID] = true
+	
+	task, err := e.GetTask(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get task %s: %w", taskID, err)
+	}
+	node := &t.TaskTreeNode{
+		TaskID:      task.TaskID,
+		Title:       task.Title,
+		Status:      task.Status,
+		DelegatorID: task.DelegatorID,
+		DelegateeID: task.DelegateeID,
+		ContractID:  task.ContractID,
+		Progress:    e.taskProgress(task),
+		Depth:       depth,
+		DependsOn:   task.DependsOn,
+	}
+	
+	for _, childID := range task.SubTaskIDs {
+		child, err := e.loadTreeNode(childID, depth+1, seen)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
+
+// taskProgress prefers the parent roll-up, then the latest monitoring event.
+func (e *Engine) taskProgress(task *t.TaskSpec) float64 {
+	switch task.Status {
+	case t.TaskCompleted, t.TaskVerifying, t.TaskVerified:
+		return 1.0
+	}
+	if len(task.SubTaskIDs) > 0 {
+		if rollup, err := e.GetRollup(task.TaskID); err == nil {
+			return rollup.Progress
+		}
+	}
+	if event, err := e.latestFigures(task.TaskID); err == nil && !event.Withholds(FieldProgress) {
+		return event.Progress
+	}
+	return 0
+}
+
+// ExportTaskTree writes a task tree as "dot" (Graphviz) or "mermaid" (flowchart).
+// Sub-task edges are solid; DependsOn edges between siblings are dashed.
+func ExportTaskTree(w io.Writer, root *t.TaskTreeNode, format string) error {
+	switch format {
+	case "dot":
+		return exportTreeDOT(w, root)
+	case "mermaid":
+		return exportTreeMermaid(w, root)
+	default:
+		return fmt.Errorf("unsupported tree format %q (want dot or mermaid)", format)
+	}
+}
+
+// walkTree visits nodes depth-first, parents before children.
+func walkTree(node *t.TaskTreeNode, visit func(*t.TaskTreeNode)) {
+	visit(node)
+	for _, child := range node.Children {
+		walkTree(child, visit)
+	}
+}
+
+// nodeLabel summarizes a node as title, ID, then status, holder and progress.
+func nodeLabel(n *t.TaskTreeNode) []string {
+	holder := n.DelegateeID
+	if holder == "" {
+		holder = "unassigned"
+	}
+	lines := []string{n.Title, n.TaskID, fmt.Sprintf("%s · %s · %.0f%%", n.Status, holder, n.Progress*100)}
+	if n.ContractID != "" {
+		lines = append(lines, n.ContractID)
+	}
+	return lines
+}
+
+// statusColor maps task status to a fill color shared by both export formats.
+func statusColor(status t.TaskStatus) string {
+	switch status {
+	case t.TaskVerified:
+		return "#b7e1a1"
+	case t.TaskCompleted, t.TaskVerifying:
+		return "#d9f0c8"
+	case t.TaskInProgress, t.TaskCheckpoint, t.TaskAssigned:
+		return "#bcd7f5"
+	case t.TaskBidding, t.TaskDecomposed:
+		return "#e4e4f7"
+	case t.TaskFailed, t.TaskCancelled:
+		return "#f4b6b6"
+	case t.TaskDisputed, t.TaskReAllocating, t.TaskPaused:
+		return "#f9dfa5"
+	default:
+		return "#eeeeee"
+	}
+}
+
+func exportTreeDOT(w io.Writer, root *t.TaskTreeNode) error {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+	}
+	
+	inTree := make(map[string]bool)
+	walkTree(root, func(n *t.TaskTreeNode) { inTree[n.TaskID] = true })
+	
+	var b strings.Builder
+	b.WriteString("digraph delegation {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	walkTree(root, func(n *t.TaskTreeNode) {
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=%s];\n",
+			quote(n.TaskID), quote(strings.Join(nodeLabel(n), "\n")), quote(statusColor(n.Status)))
+	})
+	walkTree(root, func(n *t.TaskTreeNode) {
+		for _, child := range n.Children {
+			fmt.Fprintf(&b, "  %s -> %s;\n", quote(n.TaskID), quote(child.TaskID))
+		}
+		for _, dep := range n.DependsOn {
+			if !inTree[dep] {
+				continue
+			}
+			fmt.Fprintf(&b, "  %s -> %s [style=dashed, label=\"depends on\"];\n", quote(n.TaskID), quote(dep))
+		}
+	})
+	b.WriteString("}\n")
+	
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func exportTreeMermaid(w io.Writer, root *t.TaskTreeNode) error {
+	// Task IDs may contain characters Mermaid rejects, so nodes get positional IDs
+	ids := make(map[string]string)
+	walkTree(root, func(n *t.TaskTreeNode) {
+		ids[n.TaskID] = fmt.Sprintf("n%d", len(ids))
+	})
+	escape := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace
+	
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	classes := make(map[t.TaskStatus][]string)
+	walkTree(root, func(n *t.TaskTreeNode) {
+		lines := nodeLabel(n)
+		for i := range lines {
+			lines[i] = escape(lines[i])
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n.TaskID], strings.Join(lines, "<br/>"))
+		classes[n.Status] = append(classes[n.Status], ids[n.TaskID])
+	})
+	walkTree(root, func(n *t.TaskTreeNode) {
+		for _, child := range n.Children {
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[n.TaskID], ids[child.TaskID])
+		}
+		for _, dep := range n.DependsOn {
+			if depID, ok := ids[dep]; ok {
+				fmt.Fprintf(&b, "  %s -. depends on .-> %s\n", ids[n.TaskID], depID)
+			}
+		}
+	})
+	walkTree(root, func(n *t.TaskTreeNode) {
+		members, ok := classes[n.Status]
+		if !ok {
+			return
+		}
+		delete(classes, n.Status)
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:#555\n", n.Status, statusColor(n.Status))
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(members, ","), n.Status)
+	})
+	
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -19,14 +19,22 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
+	"github.com/awgh/bencrypt/ecc"
 	"github.com/dataparency-dev/AI-delegation/delegation"
 	"github.com/dataparency-dev/AI-delegation/market"
+	"github.com/dataparency-dev/AI-delegation/notify"
 	"github.com/dataparency-dev/AI-delegation/security"
+	"github.com/dataparency-dev/AI-delegation/tracing"
 	t "github.com/dataparency-dev/AI-delegation/types"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func main() {
@@ -35,6 +43,17 @@ func main() {
 	// Uses: ConnectAPI, LoginAPI, session key management
 	// ═══════════════════════════════════════════════════════════════
 
+	// Write spans to a local file so one request can be followed end to end
+	shutdownTracing, err := tracing.Setup(tracing.Config{
+		ServiceName: "delegation-orchestrator",
+		Exporter:    tracing.ExporterFile,
+		FilePath:    "delegation-traces.jsonl",
+	})
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	engine, err := delegation.NewEngine(
 		"nats://localhost:4222", // NATS URL
 		"delegation-server",     // Server topic
@@ -46,6 +65,24 @@ func main() {
 		log.Fatalf("Failed to initialize engine: %v", err)
 	}
 
+	// Expose Prometheus metrics at http://localhost:9090/metrics
+	metrics, err := engine.EnableMetrics(prometheus.DefaultRegisterer)
+	if err != nil {
+		log.Fatalf("Failed to enable metrics: %v", err)
+	}
+	metricsServer := delegation.ServeMetrics(":9090", prometheus.DefaultGatherer)
+	defer metricsServer.Close()
+
+	// Route escalations and security alerts to overseers: every notification is
+	// logged as JSON lines, and mailed to overseers that list an email address
+	notifications := delegation.NewNotificationRouter()
+	notifications.Overseers = []string{"human-reviewer-01"}
+	defer notifications.Wait()
+	notifications.Register("stdout", &notify.Stdout{})
+	notifications.Register("file", &notify.File{Path: "notifications.jsonl"})
+	notifications.Register("email", &notify.SMTP{Addr: "localhost:25", From: "delegation@example.com"})
+	engine.Notifications = notifications
+
 	// ═══════════════════════════════════════════════════════════════
 	// STEP 2: Register Agents
 	// Uses: EntityRegister → creates entity identity
@@ -67,6 +104,19 @@ func main() {
 		log.Printf("Register orchestrator: %v", err)
 	}
 
+	// Sign this engine's audit records so tampering is detectable later
+	_, signingKey, _ := ed25519.GenerateKey(nil)
+	if err := engine.SetSigningKey(signingKey); err != nil {
+		log.Printf("Publish signing key: %v", err)
+	}
+
+	// Let delegatees seal unredacted monitoring events to this orchestrator
+	sealKey := new(ecc.KeyPair)
+	sealKey.GenerateKey()
+	if err := engine.SetEncryptionKey(sealKey); err != nil {
+		log.Printf("Publish encryption key: %v", err)
+	}
+
 	// Register specialist delegatee agents
 	coder := t.AgentProfile{
 		AgentID:      "agent-coder-01",
@@ -102,6 +152,7 @@ func main() {
 		CostPerUnit:  0.50,
 		TrustScore:   0.95,
 		Status:       t.StatusOnline,
+		Metadata:     map[string]string{"email": "reviewer@example.com"},
 	}
 
 	for _, agent := range []t.AgentProfile{coder, analyst, reviewer} {
@@ -112,6 +163,13 @@ func main() {
 
 	fmt.Println("=== Agents Registered ===")
 
+	// Track liveness: agents that miss 3 heartbeats go offline and their tasks are re-delegated
+	if err := engine.WatchHeartbeats(coder.AgentID, analyst.AgentID, reviewer.AgentID); err != nil {
+		log.Printf("Watch heartbeats: %v", err)
+	}
+	stopLiveness := engine.StartLivenessChecks()
+	defer stopLiveness()
+
 	// ═══════════════════════════════════════════════════════════════
 	// STEP 3: Create and Decompose a Task
 	// Uses: EntityRegister → task identity
@@ -174,11 +232,13 @@ func main() {
 			Title:                "Build API Backend",
 			Description:          "REST API serving analytics data to the frontend",
 			Criticality:          t.CriticalityMedium,
+			CriticalityRationale: "Read-only over verified pipeline output",
 			Complexity:           5,
 			Verifiability:        0.9, // Auto-testable
 			Reversible:           true,
 			IsLeaf:               true,
 			RequiredCapabilities: []string{"go", "testing"},
+			DependsOn:            []string{"task-data-pipeline"}, // Serves the pipeline's output
 			AutonomyLevel:        t.AutonomyAtomic,
 			MonitoringMode:       t.MonitorEventTriggered,
 			EstimatedDuration:    10800, // 3 hours
@@ -189,6 +249,7 @@ func main() {
 			Title:                "Build Visualization Layer",
 			Description:          "Interactive charts and dashboard UI",
 			Criticality:          t.CriticalityMedium,
+			CriticalityRationale: "Presentation only; errors are visible and reversible",
 			Complexity:           5,
 			Verifiability:        0.5, // Partially subjective
 			Subjectivity:         0.6,
@@ -223,6 +284,12 @@ func main() {
 			continue
 		}
 
+		// Dependent sub-tasks are released by the engine once their predecessors verify
+		if len(sub.DependsOn) > 0 {
+			fmt.Printf("Task %s waiting on %v\n", sub.TaskID, sub.DependsOn)
+			continue
+		}
+
 		// Publish for bidding
 		channel, err := engine.PublishTaskForBidding(sub)
 		if err != nil {
@@ -307,6 +374,9 @@ func main() {
 	fmt.Printf("\n=== DCT Minted: %s ===\n", dct.TokenID)
 	fmt.Printf("  Bearer: %s, Resource: %s, Expires: %v\n", dct.BearerID, dct.Resource, dct.ExpiresAt)
 
+	// Register the token with the task so cancelling the task revokes it
+	engine.RegisterTaskToken(subTasks[0].TaskID, dct)
+
 	// If the delegatee needs to sub-delegate, it attenuates the token further
 	childDCT, err := dct.Attenuate("agent-sub-worker-01",
 		security.Caveat{Type: "operation", Key: "ops", Value: "read"}, // Narrowed: no execute
@@ -316,15 +386,16 @@ func main() {
 		log.Printf("Attenuate DCT: %v", err)
 	} else {
 		fmt.Printf("  Child DCT: %s (attenuated: read-only, scoped to 2026)\n", childDCT.TokenID)
+		engine.RegisterTaskToken(subTasks[0].TaskID, childDCT)
 	}
 
-	// Validate access
-	if err := dct.ValidateAccess("read", "raw_events"); err != nil {
+	// Validate access against the registered copy, which sees revocation
+	if err := engine.ValidateTaskToken(subTasks[0].TaskID, dct, "read", "raw_events"); err != nil {
 		fmt.Printf("  Access denied: %v\n", err)
 	} else {
 		fmt.Println("  Access validated: read on raw_events")
 	}
-	if err := dct.ValidateAccess("write", "raw_events"); err != nil {
+	if err := engine.ValidateTaskToken(subTasks[0].TaskID, dct, "write", "raw_events"); err != nil {
 		fmt.Printf("  Access denied (expected): %v\n", err)
 	}
 
@@ -342,6 +413,30 @@ func main() {
 	}
 	fmt.Printf("\n=== Monitoring Channel: %s (RDID: %s) ===\n", monCh, monRDID)
 
+	// Scrub PII from event messages on sensitive tasks; keep the originals sealed
+	redactor, err := delegation.NewRedactor(t.RedactionPolicy{
+		MinSensitivity: 0.5,
+		Patterns:       delegation.DefaultPIIPatterns(),
+		SealOriginal:   true,
+	})
+	if err != nil {
+		log.Printf("Build redactor: %v", err)
+	} else {
+		engine.Redactor = redactor
+	}
+
+	// Watch the stream for stalls, regressions and budget overruns
+	engine.Detector = delegation.NewAnomalyDetector(delegation.DefaultAnomalyRules())
+	stopSweeps := engine.StartAnomalySweeps(time.Minute)
+	defer stopSweeps()
+
+	// The contract is periodic: progress is batched into 30-minute reports, the
+	// completion event flushes the batch, and silent delegatees are flagged
+	stopReports := engine.StartPeriodicReporting(time.Minute)
+	defer stopReports()
+	stopReportChecks := engine.StartReportingChecks(5 * time.Minute)
+	defer stopReportChecks()
+
 	// Simulate delegatee emitting progress events
 	events := []t.MonitorEvent{
 		{
@@ -381,6 +476,9 @@ func main() {
 		}
 		fmt.Printf("  [%s] Progress: %.0f%% — %s\n", evt.EventType, evt.Progress*100, evt.Message)
 	}
+	if report, err := engine.VerifyAuditLog("task-data-pipeline"); err == nil {
+		fmt.Printf("  Audit chain: %d records, valid=%v\n", report.Records, report.Valid)
+	}
 
 	// ═══════════════════════════════════════════════════════════════
 	// STEP 7: Verification & Reputation
@@ -390,6 +488,7 @@ func main() {
 	// ═══════════════════════════════════════════════════════════════
 
 	fmt.Println("\n=== Verification ===")
+	verifyStart := time.Now()
 
 	// Submit artifact for verification
 	engine.SubmitForVerification("task-data-pipeline", []byte(`{"tests_passed": 42, "coverage": 0.89}`))
@@ -407,6 +506,17 @@ func main() {
 	trust, _ := engine.ComputeTrustScore(winner.Bid.AgentID)
 	fmt.Printf("  Updated trust score for %s: %.3f\n", winner.Bid.AgentID, trust)
 
+	// Replay the event log to see what was known before verification came in
+	if past, err := engine.ProjectTask("task-data-pipeline", verifyStart); err == nil {
+		fmt.Printf("  Before verification the log had task-data-pipeline as %s\n", past.Status)
+	}
+
+	// Render the delegation hierarchy (paste into a Mermaid viewer, or use "dot")
+	fmt.Println("\n=== Task Tree ===")
+	if tree, err := engine.GetTaskTree("task-build-dashboard"); err == nil {
+		delegation.ExportTaskTree(os.Stdout, tree, "mermaid")
+	}
+
 	// ═══════════════════════════════════════════════════════════════
 	// STEP 8: Adaptive Coordination (Failure Scenario)
 	// Uses: Post → store trigger
@@ -417,15 +527,36 @@ func main() {
 
 	fmt.Println("\n=== Adaptive Coordination (Simulated Failure) ===")
 
+	// Responses come from a versioned policy; without one the built-in tree applies.
+	// A restarted engine picks up the published version; the example is only
+	// published the first time.
+	if policy, err := engine.ActivateResponsePolicy("cautious"); err == nil {
+		fmt.Printf("  Response policy %s v%d active\n", policy.Name, policy.Version)
+	} else if !errors.Is(err, delegation.ErrNotFound) {
+		log.Printf("Activate response policy: %v", err)
+	} else if policy, err := delegation.LoadResponsePolicy("policies/cautious.yaml"); err != nil {
+		log.Printf("Load response policy: %v", err)
+	} else if policy, err = engine.PublishResponsePolicy(*policy); err != nil {
+		log.Printf("Publish response policy: %v", err)
+	} else {
+		fmt.Printf("  Response policy %s v%d active\n", policy.Name, policy.Version)
+	}
+
 	// Simulate: the API backend agent becomes unresponsive
-	engine.RaiseTrigger(t.AdaptiveTrigger{
+	unresponsive := t.AdaptiveTrigger{
 		TriggerID:   "trigger-api-unresponsive",
 		TaskID:      "task-api-backend",
 		Type:        t.TriggerIntUnresponsive,
 		AgentID:     "agent-coder-01",
 		Description: "Agent failed to respond to 3 consecutive health checks",
 		Urgent:      false, // Task is reversible, not critical
-	})
+	}
+
+	// Dry-run first: see which rule would answer without touching the task
+	if decision, err := engine.DryRunPolicy(nil, unresponsive); err == nil {
+		fmt.Printf("  Dry run: rule %q → %s\n", decision.Rule, decision.Action)
+	}
+	engine.RaiseTrigger(unresponsive)
 	fmt.Println("  Trigger raised → engine evaluates and re-delegates")
 
 	// ═══════════════════════════════════════════════════════════════
@@ -451,11 +582,27 @@ func main() {
 
 	// Circuit breaker for an agent
 	cb := security.NewCircuitBreaker("agent-coder-01", 3, 0.4)
+	cb.OnTrip = metrics.BreakerTripped
 	cb.RecordFailure()
 	cb.RecordFailure()
 	tripped := cb.RecordFailure() // Third failure → trips
 	fmt.Printf("  Circuit breaker tripped: %v (state: %s)\n", tripped, cb.State)
 	fmt.Printf("  Agent allowed: %v\n", cb.IsAllowed())
 
+	// Report the tripped agent; the overseer is notified and the task's trigger fires
+	if tripped {
+		if err := engine.RaiseSecurityAlert(security.SecurityAlert{
+			TaskID:      "task-suspicious",
+			AgentID:     "agent-coder-01",
+			ThreatType:  security.ThreatResourceExhaust,
+			Severity:    t.CriticalityHigh,
+			Description: "circuit breaker tripped after repeated failures",
+		}); err != nil {
+			log.Printf("Raise security alert: %v", err)
+		}
+		inbox, _ := engine.GetInbox("human-reviewer-01")
+		fmt.Printf("  Overseer inbox: %d notifications\n", len(inbox))
+	}
+
 	fmt.Println("\n=== Delegation Lifecycle Complete ===")
 }
@@ -0,0 +1,182 @@
+// Package notify provides notifiers for the delegation engine's oversight
+// notifications (Sections 4.4, 4.9). Each type implements engine.Notifier and
+// is registered on an engine.NotificationRouter under a name that routes refer to.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	
+	t "github.com/dataparency-dev/AI-delegation/types"
+)
+
+// ─── Stdout ──────────────────────────────────────────────────────────────────
+
+// Stdout writes a one-line summary of each notification to W.
+type Stdout struct {
+	W io.Writer // Defaults to os.Stdout
+}
+
+// Notify implements engine.Notifier.
+func (s *Stdout) Notify(n t.Notification) error {
+	w := s.W
+	if w == nil {
+		w = os.Stdout
+	}
+	_, err := fmt.Fprintf(w, "[%s] %s %s → %s: %s — %s\n",
+		n.CreatedAt.Format(time.RFC3339), strings.ToUpper(string(n.Severity)), n.Kind,
+		strings.Join(recipientIDs(n), ","), n.Title, n.Body)
+	return err
+}
+
+// ─── File ────────────────────────────────────────────────────────────────────
+
+// File appends each notification as one JSON line to Path.
+type File struct {
+	Path string
+	
+	mu sync.Mutex
+}
+
+// Notify implements engine.Notifier.
+func (f *File) Notify(n t.Notification) error {
+	line, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out, err := os.OpenFile(f.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open notification file: %w", err)
+	}
+	defer out.Close()
+	_, err = out.Write(append(line, '\n'))
+	return err
+}
+
+// ─── Webhook ─────────────────────────────────────────────────────────────────
+
+// Webhook POSTs each notification as JSON to URL, and to the webhook of every
+// recipient that has one in its agent metadata. Headers only go to URL: a
+// recipient's webhook comes from its own metadata and must not see them.
+type Webhook struct {
+	URL     string            // May be empty when only recipient webhooks are used
+	Headers map[string]string // Extra request headers for URL, e.g. an authorization token
+	Client  *http.Client      // Defaults to a client with a 10s timeout
+}
+
+// Notify implements engine.Notifier.
+func (w *Webhook) Notify(n t.Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	
+	var failed []string
+	if w.URL != "" {
+		if err := w.post(w.URL, body, w.Headers); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	for _, r := range n.Recipients {
+		if r.Webhook == "" {
+			continue
+		}
+		if err := w.post(r.Webhook, body, nil); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("webhook delivery: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (w *Webhook) post(url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", url, err)
+	}
+	defer rsp.Body.Close()
+	io.Copy(io.Discard, rsp.Body)
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return fmt.Errorf("%s: status %d", url, rsp.StatusCode)
+	}
+	return nil
+}
+
+// ─── SMTP ────────────────────────────────────────────────────────────────────
+
+// SMTP mails each notification to the recipients' email addresses, taken from
+// their agent metadata, plus any fixed To addresses.
+type SMTP struct {
+	Addr string    // host:port of the mail server
+	Auth smtp.Auth // Nil sends unauthenticated
+	From string
+	To   []string // Always copied, e.g. an on-call list
+}
+
+// Notify implements engine.Notifier.
+func (s *SMTP) Notify(n t.Notification) error {
+	to := append([]string(nil), s.To...)
+	for _, r := range n.Recipients {
+		if r.Email != "" {
+			to = append(to, r.Email)
+		}
+	}
+	if len(to) == 0 {
+		return nil // Nobody on this route has an address
+	}
+	
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: [%s] %s\r\n", strings.ToUpper(string(n.Severity)), n.Title)
+	fmt.Fprintf(&msg, "Date: %s\r\n", n.CreatedAt.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(n.Body + "\r\n\r\n")
+	fmt.Fprintf(&msg, "Kind: %s\r\n", n.Kind)
+	if n.TaskID != "" {
+		fmt.Fprintf(&msg, "Task: %s (criticality %s)\r\n", n.TaskID, n.TaskCriticality)
+	}
+	if n.AgentID != "" {
+		fmt.Fprintf(&msg, "Agent: %s\r\n", n.AgentID)
+	}
+	fmt.Fprintf(&msg, "Notification: %s\r\n", n.NotificationID)
+	
+	if err := smtp.SendMail(s.Addr, s.Auth, s.From, to, msg.Bytes()); err != nil {
+		return fmt.Errorf("send mail via %s: %w", s.Addr, err)
+	}
+	return nil
+}
+
+func recipientIDs(n t.Notification) []string {
+	ids := make([]string, len(n.Recipients))
+	for i, r := range n.Recipients {
+		ids[i] = r.AgentID
+	}
+	return ids
+}
@@ -135,6 +135,10 @@ type CircuitBreaker struct {
 	CooldownPeriod   time.Duration
 	State            CBState
 	LastTripped      time.Time
+	
+	// Called when the breaker moves into CBOpen, with reason "failures" or
+	// "trust_drop"; failures recorded while already open do not call it again
+	OnTrip func(agentID, reason string) `json:"-"`
 }
 
 type CBState string
@@ -159,8 +163,7 @@ func NewCircuitBreaker(agentID string, failureThreshold int, trustFloor float64)
 func (cb *CircuitBreaker) RecordFailure() bool {
 	cb.FailureCount++
 	if cb.FailureCount >= cb.FailureThreshold {
-		cb.State = CBOpen
-		cb.LastTripped = time.Now()
+		cb.trip("failures")
 		return true // Tripped
 	}
 	return false
@@ -175,13 +178,23 @@ func (cb *CircuitBreaker) RecordSuccess() {
 // CheckTrustDrop trips the breaker if trust drops below the floor.
 func (cb *CircuitBreaker) CheckTrustDrop(currentTrust float64) bool {
 	if currentTrust < cb.TrustFloor {
-		cb.State = CBOpen
-		cb.LastTripped = time.Now()
+		cb.trip("trust_drop")
 		return true
 	}
 	return false
 }
 
+// trip opens the breaker and restarts its cooldown, notifying OnTrip only
+// when it was not already open.
+func (cb *CircuitBreaker) trip(reason string) {
+	wasOpen := cb.State == CBOpen
+	cb.State = CBOpen
+	cb.LastTripped = time.Now()
+	if !wasOpen && cb.OnTrip != nil {
+		cb.OnTrip(cb.AgentID, reason)
+	}
+}
+
 // IsAllowed checks if the agent is currently allowed to accept tasks.
 func (cb *CircuitBreaker) IsAllowed() bool {
 	switch cb.State {
@@ -0,0 +1,97 @@
+// Package tracing configures OpenTelemetry for the delegation engine. The
+// engine creates its spans through the global tracer provider; Setup installs
+// one with the chosen exporter, including stdout and file exporters for
+// offline use.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporter names accepted by Config.Exporter.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Config selects how spans are exported.
+type Config struct {
+	ServiceName string  `json:"service_name"`
+	Exporter    string  `json:"exporter"`     // "none", "stdout" or "file"
+	FilePath    string  `json:"file_path"`    // Spans are appended here when Exporter is "file"
+	SampleRatio float64 `json:"sample_ratio"` // Fraction of new traces kept; 0 keeps all
+}
+
+// Setup installs a global tracer provider and the W3C trace-context
+// propagator. Call the returned shutdown function to flush spans on exit.
+func Setup(cfg Config) (shutdown func(context.Context) error, err error) {
+	if cfg.Exporter == "" || cfg.Exporter == ExporterNone {
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		return func(context.Context) error { return nil }, nil
+	}
+	
+	var w io.Writer = os.Stdout
+	var file *os.File
+	switch cfg.Exporter {
+	case ExporterStdout:
+	case ExporterFile:
+		if cfg.FilePath == "" {
+			return nil, fmt.Errorf("file exporter needs a file path")
+		}
+		file, err = os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open trace file: %w", err)
+		}
+		w = file
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+	
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, fmt.Errorf("create %s exporter: %w", cfg.Exporter, err)
+	}
+	shutdownProvider := SetupWithExporter(exporter, cfg)
+	return func(ctx context.Context) error {
+		err := shutdownProvider(ctx)
+		if file != nil {
+			if cerr := file.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
+
+// SetupWithExporter installs a global tracer provider around any span
+// exporter, such as an OTLP exporter built by the caller.
+func SetupWithExporter(exporter sdktrace.SpanExporter, cfg Config) (shutdown func(context.Context) error) {
+	service := cfg.ServiceName
+	if service == "" {
+		service = "ai-delegation"
+	}
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))
+	}
+	
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown
+}
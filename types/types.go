@@ -3,7 +3,10 @@
 // delegation contracts, reputation records, and monitoring events.
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ─── Agent Identity & Capabilities ───────────────────────────────────────────
 
@@ -28,19 +31,22 @@ const (
 // AgentProfile is the core identity registered as an Entity in the NATS-backed store.
 // Corresponds to the paper's delegator/delegatee agent card concept and A2A agent cards.
 type AgentProfile struct {
-	AgentID      string            `json:"agent_id"`      // Unique identifier (maps to entity identity)
-	Name         string            `json:"name"`          // Human-readable name
-	Type         AgentType         `json:"type"`          // AI or Human
-	Role         AgentRole         `json:"role"`          // Delegator, Delegatee, Both, Overseer
-	Capabilities []string          `json:"capabilities"`  // Skills/domains this agent can handle
-	MaxLoad      int               `json:"max_load"`      // Max concurrent tasks (span of control)
-	CurrentLoad  int               `json:"current_load"`  // Current active tasks
-	Status       AgentStatus       `json:"status"`        // Online, Busy, Offline
-	TrustScore   float64           `json:"trust_score"`   // Aggregate reputation [0.0 - 1.0]
-	CostPerUnit  float64           `json:"cost_per_unit"` // Cost rate
-	Metadata     map[string]string `json:"metadata"`      // Extensible fields
-	RegisteredAt time.Time         `json:"registered_at"`
-	LastSeenAt   time.Time         `json:"last_seen_at"`
+	AgentID       string            `json:"agent_id"`      // Unique identifier (maps to entity identity)
+	Name          string            `json:"name"`          // Human-readable name
+	Type          AgentType         `json:"type"`          // AI or Human
+	Role          AgentRole         `json:"role"`          // Delegator, Delegatee, Both, Overseer
+	Capabilities  []string          `json:"capabilities"`  // Skills/domains this agent can handle
+	MaxLoad       int               `json:"max_load"`      // Max concurrent tasks (span of control)
+	CurrentLoad   int               `json:"current_load"`  // Current active tasks
+	Status        AgentStatus       `json:"status"`        // Online, Busy, Offline
+	TrustScore    float64           `json:"trust_score"`   // Aggregate reputation [0.0 - 1.0]
+	CostPerUnit   float64           `json:"cost_per_unit"` // Cost rate
+	Metadata      map[string]string `json:"metadata"`      // Extensible fields
+	RegisteredAt  time.Time         `json:"registered_at"`
+	LastSeenAt    time.Time         `json:"last_seen_at"`
+	SigningKey    string            `json:"signing_key,omitempty"`    // Base64 ed25519 public key for audit signatures
+	SigningKeys   map[string]string `json:"signing_keys,omitempty"`   // Every audit key by key ID, kept after rotation
+	EncryptionKey string            `json:"encryption_key,omitempty"` // Base64 ECC public key sealed monitoring payloads are encrypted to
 }
 
 type AgentStatus string
@@ -51,6 +57,15 @@ const (
 	StatusOffline AgentStatus = "offline"
 )
 
+// Heartbeat is an agent's periodic liveness beacon on the heartbeat channel.
+type Heartbeat struct {
+	AgentID     string      `json:"agent_id"`
+	Status      AgentStatus `json:"status,omitempty"`       // Empty keeps the current status (offline becomes online)
+	CurrentLoad *int        `json:"current_load,omitempty"` // Nil leaves the recorded load unchanged
+	Sequence    uint64      `json:"sequence"`
+	SentAt      time.Time   `json:"sent_at"`
+}
+
 // ─── Task Characteristics (Section 2.2 of the paper) ─────────────────────────
 
 // Criticality levels for tasks.
@@ -63,6 +78,21 @@ const (
 	CriticalityCritical Criticality = "critical"
 )
 
+// Rank orders criticality levels so a sub-task can be compared with its parent.
+// Unknown levels rank as low.
+func (c Criticality) Rank() int {
+	switch c {
+	case CriticalityMedium:
+		return 1
+	case CriticalityHigh:
+		return 2
+	case CriticalityCritical:
+		return 3
+	default:
+		return 0
+	}
+}
+
 // TaskStatus tracks lifecycle states.
 type TaskStatus string
 
@@ -80,6 +110,7 @@ const (
 	TaskVerified     TaskStatus = "verified"
 	TaskDisputed     TaskStatus = "disputed"
 	TaskReAllocating TaskStatus = "re_allocating"
+	TaskPaused       TaskStatus = "paused" // Held by an adaptive response; the delegatee keeps the slot
 )
 
 // TaskSpec defines a task or sub-task, incorporating all characteristics from Section 2.2.
@@ -92,6 +123,7 @@ type TaskSpec struct {
 	Description  string      `json:"description"`
 	Status       TaskStatus  `json:"status"`
 	Criticality  Criticality `json:"criticality"`
+	Priority     int         `json:"priority"` // Scheduling order; higher runs first and may preempt lower
 	
 	// Task Characteristics (Section 2.2)
 	Complexity         int     `json:"complexity"`          // 1-10 scale
@@ -105,7 +137,24 @@ type TaskSpec struct {
 	
 	// Decomposition
 	SubTaskIDs []string `json:"sub_task_ids,omitempty"`
-	IsLeaf     bool     `json:"is_leaf"` // True if no further decomposition
+	DependsOn  []string `json:"depends_on,omitempty"` // Sibling sub-tasks that must be verified first
+	IsLeaf     bool     `json:"is_leaf"`              // True if no further decomposition
+	
+	// Required when a sub-task is less critical than its parent
+	CriticalityRationale string `json:"criticality_rationale,omitempty"`
+	
+	// Sub-delegation: 0 for tasks issued by the original delegator, +1 per delegatee hop
+	DelegationDepth int `json:"delegation_depth"`
+	
+	// Who answers to whom, from the original delegator down to the current delegatee
+	DelegationChain []DelegationLink `json:"delegation_chain,omitempty"`
+	
+	// How child outcomes roll up to this task (nil = all children required)
+	CompletionPolicy *CompletionPolicy `json:"completion_policy,omitempty"`
+	
+	// W3C trace context of the span that created the task; engine spans about
+	// the task, on either side of a delegation, join this trace
+	TraceContext map[string]string `json:"trace_context,omitempty"`
 	
 	// Execution constraints
 	RequiredCapabilities []string            `json:"required_capabilities"`
@@ -113,6 +162,7 @@ type TaskSpec struct {
 	MonitoringMode       MonitoringMode      `json:"monitoring_mode"`
 	Permissions          []Permission        `json:"permissions"`
 	VerificationPolicy   *VerificationPolicy `json:"verification_policy,omitempty"`
+	ContractID           string              `json:"contract_id,omitempty"` // Active contract, set on assignment
 	
 	// Timing
 	CreatedAt   time.Time  `json:"created_at"`
@@ -129,6 +179,19 @@ const (
 	AutonomyOpenEnd AutonomyLevel = "open_ended" // Full decomposition authority
 )
 
+// Rank orders autonomy levels so a child can be checked against its parent.
+// Unknown levels rank as atomic.
+func (a AutonomyLevel) Rank() int {
+	switch a {
+	case AutonomyBounded:
+		return 1
+	case AutonomyOpenEnd:
+		return 2
+	default:
+		return 0
+	}
+}
+
 type MonitoringMode string
 
 const (
@@ -138,6 +201,176 @@ const (
 	MonitorOutcomeOnly    MonitoringMode = "outcome_only"
 )
 
+// Discloses reports whether an event of the given type may leave the emitter
+// under this mode. Continuous and periodic disclose everything (periodic in
+// batches); event-triggered withholds routine progress; outcome-only discloses
+// only how the task ended.
+func (m MonitoringMode) Discloses(eventType MonitorEventType) bool {
+	switch m {
+	case MonitorOutcomeOnly:
+		switch eventType {
+		case EventTaskCompleted, EventTaskFailed, EventTaskCancelled:
+			return true
+		}
+		return false
+	case MonitorEventTriggered:
+		return eventType != EventProgressUpdate
+	default:
+		return true
+	}
+}
+
+// CompletionPolicy decides when a decomposed task is done based on its children.
+type CompletionPolicy struct {
+	Mode   string  `json:"mode"`   // "all" or "quorum"
+	Quorum float64 `json:"quorum"` // Fraction of children that must verify (quorum mode)
+}
+
+const (
+	CompletionAll    = "all"
+	CompletionQuorum = "quorum"
+)
+
+// TaskRollup aggregates a parent task's children. Stored under Tasks/{id}/rollup.
+type TaskRollup struct {
+	TaskID     string     `json:"task_id"`
+	Children   int        `json:"children"`
+	Verified   int        `json:"verified"`
+	Completed  int        `json:"completed"` // Done but not yet verified
+	Failed     int        `json:"failed"`    // Failed or cancelled
+	InFlight   int        `json:"in_flight"`
+	Required   int        `json:"required"` // Verified children the policy needs
+	Progress   float64    `json:"progress"` // Duration-weighted 0.0-1.0
+	Spend      float64    `json:"spend"`    // Sum of children's latest ResourceUse
+	Status     TaskStatus `json:"status"`   // Parent status the roll-up implies
+	ComputedAt time.Time  `json:"computed_at"`
+}
+
+// TaskInput is a predecessor's verified result artifact handed to a dependent task.
+type TaskInput struct {
+	FromTaskID string    `json:"from_task_id"`
+	Artifact   []byte    `json:"artifact"`
+	StagedAt   time.Time `json:"staged_at"`
+}
+
+// Checkpoint is partial work a delegatee submits mid-task, stored under
+// Tasks/{id}/checkpoint_{writer}_{n}. A verified checkpoint is handed to
+// whichever agent takes the task over on re-delegation. The verdict fields are
+// filled from CheckpointVerdicts when it is read back.
+type Checkpoint struct {
+	TaskID       string            `json:"task_id"`
+	WriterID     string            `json:"writer_id"` // Engine that stored it; sequences count per writer
+	Sequence     int               `json:"sequence"`  // n in checkpoint_{writer}_{n}, from 1
+	AgentID      string            `json:"agent_id"`
+	Progress     float64           `json:"progress"` // 0.0-1.0 at this checkpoint
+	Artifact     []byte            `json:"artifact,omitempty"`
+	ArtifactHash string            `json:"artifact_hash"` // Hex SHA-256 of Artifact
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Verified     bool              `json:"verified"`
+	Rejected     bool              `json:"rejected"`
+	VerifiedBy   string            `json:"verified_by,omitempty"`
+	VerifiedAt   *time.Time        `json:"verified_at,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// CheckpointVerdict is a verifier's decision on one checkpoint, kept apart from
+// the checkpoint so the delegatee's and the verifier's engines never write the
+// same record.
+type CheckpointVerdict struct {
+	TaskID     string    `json:"task_id"`
+	WriterID   string    `json:"writer_id"`
+	Sequence   int       `json:"sequence"`
+	Passed     bool      `json:"passed"`
+	VerifierID string    `json:"verifier_id"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// PreemptionPolicy lets a higher-priority task displace lower-priority work on
+// a saturated agent. The zero value disables preemption.
+type PreemptionPolicy struct {
+	Enabled         bool `json:"enabled"`
+	MinPriorityGap  int  `json:"min_priority_gap"` // Victim must be at least this much lower (min 1)
+	TrustCheckpoint bool `json:"trust_checkpoint"` // Treat the preempted agent's checkpoint as verified
+}
+
+// PreemptionNotice asks a delegatee to checkpoint and release a task. The
+// delegatee replies with a Checkpoint holding its partial work.
+type PreemptionNotice struct {
+	TaskID   string    `json:"task_id"`
+	ByTaskID string    `json:"by_task_id"` // Higher-priority task taking the slot
+	Priority int       `json:"priority"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// CheckpointRequest asks a delegatee to checkpoint its work now, and to hold
+// the task until it is resumed when Pause is set. The delegatee replies with a
+// Checkpoint.
+type CheckpointRequest struct {
+	TaskID   string    `json:"task_id"`
+	Reason   string    `json:"reason"`
+	Pause    bool      `json:"pause"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// TaskCancellation records a cascading cancellation, stored under
+// Tasks/{root}/cancellation and sent to each affected delegatee.
+type TaskCancellation struct {
+	RootTaskID  string    `json:"root_task_id"`
+	Reason      string    `json:"reason"`
+	CancelledBy string    `json:"cancelled_by"`
+	Cancelled   []string  `json:"cancelled"`         // Every task moved to cancelled, leaves first
+	Skipped     []string  `json:"skipped,omitempty"` // Already verified or cancelled
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+// TaskTreeNode is one task in a delegation hierarchy returned by GetTaskTree.
+type TaskTreeNode struct {
+	TaskID      string          `json:"task_id"`
+	Title       string          `json:"title"`
+	Status      TaskStatus      `json:"status"`
+	DelegatorID string          `json:"delegator_id"`
+	DelegateeID string          `json:"delegatee_id,omitempty"`
+	ContractID  string          `json:"contract_id,omitempty"`
+	Progress    float64         `json:"progress"` // 0.0-1.0, rolled up for parents
+	Depth       int             `json:"depth"`    // 0 at the requested root
+	DependsOn   []string        `json:"depends_on,omitempty"`
+	Children    []*TaskTreeNode `json:"children,omitempty"`
+}
+
+// DecompositionCheck names one consistency rule applied to a decomposition.
+type DecompositionCheck string
+
+const (
+	CheckVerifiability DecompositionCheck = "verifiability" // Non-leaf sub-tasks must be verifiable
+	CheckDependencies  DecompositionCheck = "dependencies"  // DependsOn edges form a sibling DAG
+	CheckBudget        DecompositionCheck = "budget"        // Child budgets sum within the parent's
+	CheckDeadline      DecompositionCheck = "deadline"      // Child deadlines within the parent's
+	CheckCapabilities  DecompositionCheck = "capabilities"  // Children cover the parent's capabilities
+	CheckCriticality   DecompositionCheck = "criticality"   // No unjustified downgrade
+	CheckPermissions   DecompositionCheck = "permissions"   // Child permissions within the parent's
+)
+
+// DecompositionViolation is one failed check. SubTaskID is empty for checks
+// over the decomposition as a whole.
+type DecompositionViolation struct {
+	Check     DecompositionCheck `json:"check"`
+	SubTaskID string             `json:"sub_task_id,omitempty"`
+	Message   string             `json:"message"`
+}
+
+// DecompositionReport collects every violation found in a proposed decomposition.
+type DecompositionReport struct {
+	ParentTaskID string                   `json:"parent_task_id"`
+	SubTasks     int                      `json:"sub_tasks"`
+	Violations   []DecompositionViolation `json:"violations,omitempty"`
+	CheckedAt    time.Time                `json:"checked_at"`
+}
+
+// OK reports whether the decomposition passed every check.
+func (r *DecompositionReport) OK() bool {
+	return len(r.Violations) == 0
+}
+
 // ─── Contracts & Bidding (Section 4.2) ───────────────────────────────────────
 
 // Bid represents a delegatee's offer to execute a task.
@@ -164,10 +397,21 @@ type DelegationContract struct {
 	Status        ContractStatus `json:"status"`
 	Permissions   []Permission   `json:"permissions"`
 	BackupAgentID string         `json:"backup_agent_id,omitempty"`
+	Version       int            `json:"version"`    // Incremented on each accepted amendment
+	Extensions    int            `json:"extensions"` // Accepted budget/deadline extensions so far
 	CreatedAt     time.Time      `json:"created_at"`
 	SignedAt      *time.Time     `json:"signed_at,omitempty"`
 }
 
+// DelegationLink is one hand-off in a task's delegation chain.
+type DelegationLink struct {
+	TaskID      string    `json:"task_id"`
+	DelegatorID string    `json:"delegator_id"`
+	DelegateeID string    `json:"delegatee_id"`
+	ContractID  string    `json:"contract_id"`
+	LinkedAt    time.Time `json:"linked_at"`
+}
+
 type ContractTerms struct {
 	MaxCost           float64        `json:"max_cost"`
 	Deadline          time.Time      `json:"deadline"`
@@ -177,18 +421,142 @@ type ContractTerms struct {
 	PenaltyRate       float64        `json:"penalty_rate"`      // Per-unit penalty for SLA breach
 	DisputePeriod     int64          `json:"dispute_period"`    // Seconds after completion
 	VerificationMode  string         `json:"verification_mode"` // "direct", "third_party", "consensus"
+	MaxExtensions     int            `json:"max_extensions"`    // Extensions allowed before escalation (0 = engine default)
 }
 
 type ContractStatus string
 
 const (
-	ContractDraft     ContractStatus = "draft"
-	ContractActive    ContractStatus = "active"
-	ContractCompleted ContractStatus = "completed"
-	ContractBreached  ContractStatus = "breached"
-	ContractDisputed  ContractStatus = "disputed"
+	ContractDraft      ContractStatus = "draft"
+	ContractActive     ContractStatus = "active"
+	ContractCompleted  ContractStatus = "completed"
+	ContractBreached   ContractStatus = "breached"
+	ContractDisputed   ContractStatus = "disputed"
+	ContractTerminated ContractStatus = "terminated" // Ended early by cancellation
 )
 
+// FailoverOffer is sent to a contract's backup agent over the agent channel
+// when the primary delegatee fails. It is the fast path ahead of re-bidding.
+type FailoverOffer struct {
+	TaskID      string        `json:"task_id"`
+	ContractID  string        `json:"contract_id"` // Contract being taken over
+	DelegatorID string        `json:"delegator_id"`
+	PrevAgentID string        `json:"prev_agent_id"`
+	Terms       ContractTerms `json:"terms"`
+	Permissions []Permission  `json:"permissions"`
+	Reason      string        `json:"reason"`
+	ResumeFrom  *Checkpoint   `json:"resume_from,omitempty"` // Latest verified checkpoint, if any
+	OfferedAt   time.Time     `json:"offered_at"`
+}
+
+// FailoverReply is the backup agent's answer to a FailoverOffer.
+type FailoverReply struct {
+	TaskID   string `json:"task_id"`
+	AgentID  string `json:"agent_id"`
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"` // Why the offer was declined
+}
+
+// ContractAmendment proposes a change to an active contract's terms. It takes
+// effect only once the counter-party accepts it (mutual consent).
+type ContractAmendment struct {
+	AmendmentID    string          `json:"amendment_id"`
+	ContractID     string          `json:"contract_id"`
+	TaskID         string          `json:"task_id"`
+	ProposedBy     string          `json:"proposed_by"`
+	BaseVersion    int             `json:"base_version"`   // Contract version the proposal was made against
+	TargetVersion  int             `json:"target_version"` // Contract version it produces if accepted
+	NewMaxCost     *float64        `json:"new_max_cost,omitempty"`
+	NewDeadline    *time.Time      `json:"new_deadline,omitempty"`
+	NewInterval    *int64          `json:"new_reporting_interval,omitempty"`
+	Reason         string          `json:"reason"`
+	Status         AmendmentStatus `json:"status"`
+	ResponseReason string          `json:"response_reason,omitempty"`
+	ProposedAt     time.Time       `json:"proposed_at"`
+	RespondedAt    *time.Time      `json:"responded_at,omitempty"`
+}
+
+type AmendmentStatus string
+
+const (
+	AmendmentProposed AmendmentStatus = "proposed"
+	AmendmentAccepted AmendmentStatus = "accepted"
+	AmendmentRejected AmendmentStatus = "rejected"
+	AmendmentStale    AmendmentStatus = "stale" // Contract changed since proposal
+)
+
+// IsExtension reports whether the amendment loosens budget or deadline, which
+// counts against the contract's extension limit.
+func (a ContractAmendment) IsExtension(current ContractTerms) bool {
+	if a.NewMaxCost != nil && *a.NewMaxCost > current.MaxCost {
+		return true
+	}
+	return a.NewDeadline != nil && a.NewDeadline.After(current.Deadline)
+}
+
+// AgentMessage is the envelope for everything sent over a delegator↔delegatee
+// agent channel, so one channel can carry several message kinds.
+type AgentMessage struct {
+	Type    AgentMessageType `json:"type"`
+	TaskID  string           `json:"task_id"`
+	From    string           `json:"from"`
+	Payload json.RawMessage  `json:"payload"`
+	SentAt  time.Time        `json:"sent_at"`
+	
+	TraceContext map[string]string `json:"trace_context,omitempty"` // W3C trace context of the sending span
+}
+
+type AgentMessageType string
+
+const (
+	MsgFailoverOffer     AgentMessageType = "failover_offer"
+	MsgAmendmentProposal AgentMessageType = "amendment_proposal"
+	MsgAmendmentResponse AgentMessageType = "amendment_response"
+	MsgSubDelegated      AgentMessageType = "sub_delegated"
+	MsgResumeCheckpoint  AgentMessageType = "resume_checkpoint"
+	MsgTaskCancelled     AgentMessageType = "task_cancelled"
+	MsgPreempted         AgentMessageType = "preempted"
+	MsgCheckpointRequest AgentMessageType = "checkpoint_request"
+	MsgTaskResumed       AgentMessageType = "task_resumed"
+)
+
+// ─── Settlement & Invoicing ──────────────────────────────────────────────────
+
+// SettlementRecord is what a delegator owes a delegatee once a task is verified.
+// Stored under Contracts/{contract_id}/settlement.
+type SettlementRecord struct {
+	SettlementID  string    `json:"settlement_id"`
+	ContractID    string    `json:"contract_id"`
+	TaskID        string    `json:"task_id"`
+	DelegatorID   string    `json:"delegator_id"`
+	DelegateeID   string    `json:"delegatee_id"`
+	AgreedPrice   float64   `json:"agreed_price"`   // Accepted bid's estimated cost
+	ResourceUse   float64   `json:"resource_use"`   // Final reported consumption
+	Billable      float64   `json:"billable"`       // Usage capped at the contract's MaxCost
+	LateSeconds   int64     `json:"late_seconds"`   // Delivery after the contracted deadline
+	Penalty       float64   `json:"penalty"`        // PenaltyRate × hours late, capped at Billable
+	AmountDue     float64   `json:"amount_due"`     // Billable − Penalty
+	EscrowHeld    float64   `json:"escrow_held"`    // Escrow deposited at contract time
+	EscrowApplied float64   `json:"escrow_applied"` // Portion of escrow paid out
+	EscrowRefund  float64   `json:"escrow_refund"`  // Escrow returned to the delegator
+	BalanceDue    float64   `json:"balance_due"`    // Still payable after escrow
+	CompletedAt   time.Time `json:"completed_at"`
+	SettledAt     time.Time `json:"settled_at"`
+}
+
+// Invoice groups a delegator's settlements over a billing period.
+type Invoice struct {
+	InvoiceID    string             `json:"invoice_id"`
+	DelegatorID  string             `json:"delegator_id"`
+	PeriodStart  time.Time          `json:"period_start"`
+	PeriodEnd    time.Time          `json:"period_end"`
+	Lines        []SettlementRecord `json:"lines"`
+	TotalDue     float64            `json:"total_due"`
+	TotalEscrow  float64            `json:"total_escrow_applied"`
+	TotalBalance float64            `json:"total_balance"`
+	IssuedAt     time.Time          `json:"issued_at"`
+}
+
 // ─── Permissions (Section 4.7) ───────────────────────────────────────────────
 
 // Permission implements privilege attenuation — each sub-delegation narrows scope.
@@ -237,6 +605,7 @@ const (
 	EventBudgetOverrun   MonitorEventType = "BUDGET_OVERRUN"
 	EventSecurityAlert   MonitorEventType = "SECURITY_ALERT"
 	EventAgentUnresp     MonitorEventType = "AGENT_UNRESPONSIVE"
+	EventTaskCancelled   MonitorEventType = "TASK_CANCELLED"
 )
 
 type MonitorEvent struct {
@@ -249,6 +618,111 @@ type MonitorEvent struct {
 	ResourceUse float64          `json:"resource_use"` // Budget consumed so far
 	Message     string           `json:"message"`
 	Timestamp   time.Time        `json:"timestamp"`
+	Redacted    []string         `json:"redacted,omitempty"` // Fields withheld or scrubbed in this copy
+}
+
+// Withholds reports whether a field was withheld from this copy of the event,
+// so a zero value there means "not disclosed" rather than zero.
+func (m MonitorEvent) Withholds(field string) bool {
+	for _, f := range m.Redacted {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactionPolicy controls what a monitoring event reveals once it leaves the
+// delegatee. Structural fields (event_id, task_id, agent_id, event_type,
+// timestamp) are always disclosed.
+type RedactionPolicy struct {
+	MinSensitivity float64             `json:"min_sensitivity"` // Apply to tasks at or above this ContextSensitivity
+	Patterns       []RedactionPattern  `json:"patterns"`        // Scrubbed from the message
+	AllowedFields  []string            `json:"allowed_fields"`  // Of severity, progress, resource_use, message; empty = all
+	ObserverFields map[string][]string `json:"observer_fields"` // Extra fields disclosed to specific observers, by agent ID
+	SealOriginal   bool                `json:"seal_original"`   // Keep the unredacted event, encrypted to the delegator
+}
+
+// RedactionPattern replaces every match of a regular expression.
+type RedactionPattern struct {
+	Name        string `json:"name"`
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement,omitempty"` // Default "[REDACTED:{name}]"
+}
+
+// MonitoringViolation records a delegatee reporting outside its contracted
+// monitoring mode, stored under Monitoring/{task_id}/violations.
+type MonitoringViolation struct {
+	TaskID     string         `json:"task_id"`
+	AgentID    string         `json:"agent_id"`
+	Mode       MonitoringMode `json:"mode"`
+	Kind       string         `json:"kind"`               // "undisclosable_event", "missed_report"
+	EventID    string         `json:"event_id,omitempty"` // Offending event, for undisclosable_event
+	Detail     string         `json:"detail"`
+	DetectedAt time.Time      `json:"detected_at"`
+}
+
+// TaskLiveState is the monitoring aggregator's current view of a task, derived
+// from its event stream. Rates are per hour; projections are nil until the task
+// has made measurable progress.
+type TaskLiveState struct {
+	TaskID         string           `json:"task_id"`
+	AgentID        string           `json:"agent_id"`
+	LastEventType  MonitorEventType `json:"last_event_type"`
+	Events         int              `json:"events"`
+	Progress       float64          `json:"progress"`
+	Spend          float64          `json:"spend"`
+	MaxBudget      float64          `json:"max_budget"`
+	BudgetUsed     float64          `json:"budget_used"`     // Spend / MaxBudget
+	BurnRate       float64          `json:"burn_rate"`       // Spend per hour
+	ProgressRate   float64          `json:"progress_rate"`   // Progress per hour
+	ProjectedSpend float64          `json:"projected_spend"` // Spend at 100% at the current cost per unit of progress
+	FirstEventAt   time.Time        `json:"first_event_at"`
+	LastEventAt    time.Time        `json:"last_event_at"`
+	SinceLastEvent float64          `json:"since_last_event"` // Seconds, as of the query
+	ETA            *time.Time       `json:"eta,omitempty"`
+	Deadline       *time.Time       `json:"deadline,omitempty"`
+	DeadlineSlack  *float64         `json:"deadline_slack,omitempty"` // Seconds between ETA and deadline; negative = late
+}
+
+// AuditRecord is one link in a task's hash-chained audit log, stored under
+// Monitoring/{task_id}/audit_{n}. Hash covers every other field except
+// Signature; Signature is the signer's ed25519 signature over Hash.
+type AuditRecord struct {
+	TaskID     string          `json:"task_id"`
+	Sequence   uint64          `json:"sequence"` // From 1 per signer, no gaps
+	Kind       string          `json:"kind"`     // "monitor_event", "trigger"
+	RecordID   string          `json:"record_id"`
+	Payload    json.RawMessage `json:"payload"`
+	PrevHash   string          `json:"prev_hash"` // Hex SHA-256; empty for the first record
+	SignerID   string          `json:"signer_id"`
+	KeyID      string          `json:"key_id,omitempty"` // Signing key used; empty if unsigned
+	RecordedAt time.Time       `json:"recorded_at"`
+	Hash       string          `json:"hash"`
+	Signature  string          `json:"signature,omitempty"` // Base64; empty if the signer has no key
+}
+
+// AuditHead points at the latest record in one signer's audit chain for a task.
+type AuditHead struct {
+	Sequence uint64 `json:"sequence"`
+	Hash     string `json:"hash"`
+}
+
+// AuditIssue is one problem VerifyAuditLog found.
+type AuditIssue struct {
+	SignerID string `json:"signer_id,omitempty"` // Chain the issue was found in
+	Sequence uint64 `json:"sequence"`
+	Problem  string `json:"problem"` // "gap", "reordered", "modified", "broken_link", "bad_signature", "unsigned", "head_mismatch", "unindexed", "head_missing", "empty"
+	Detail   string `json:"detail"`
+}
+
+// AuditReport is the result of verifying a task's audit chain.
+type AuditReport struct {
+	TaskID     string       `json:"task_id"`
+	Records    int          `json:"records"`
+	Valid      bool         `json:"valid"`
+	Issues     []AuditIssue `json:"issues,omitempty"`
+	VerifiedAt time.Time    `json:"verified_at"`
 }
 
 // ─── Reputation (Section 4.6) ────────────────────────────────────────────────
@@ -265,6 +739,100 @@ type ReputationRecord struct {
 	RecordedAt       time.Time `json:"recorded_at"`
 }
 
+// ─── Domain Events ───────────────────────────────────────────────────────────
+
+type DomainEventType string
+
+const (
+	DomainTaskCreated      DomainEventType = "task_created"      // Data: TaskSpec
+	DomainTaskUpdated      DomainEventType = "task_updated"      // Data: TaskSpec
+	DomainTaskDecomposed   DomainEventType = "task_decomposed"   // Data: TaskDecomposition
+	DomainBidSubmitted     DomainEventType = "bid_submitted"     // Data: Bid
+	DomainBidAccepted      DomainEventType = "bid_accepted"      // Data: DelegationContract
+	DomainContractUpdated  DomainEventType = "contract_updated"  // Data: DelegationContract
+	DomainMonitorRecorded  DomainEventType = "monitor_recorded"  // Data: MonitorEvent
+	DomainTriggerRaised    DomainEventType = "trigger_raised"    // Data: AdaptiveTrigger
+	DomainTaskVerified     DomainEventType = "task_verified"     // Data: VerificationResult
+	DomainReputationRecord DomainEventType = "reputation_record" // Data: ReputationRecord
+)
+
+// DomainEvent is one entry in the engine's append-only event log. Replaying
+// the log in Sequence order rebuilds task, contract and reputation state.
+type DomainEvent struct {
+	Sequence uint64          `json:"sequence"`
+	Type     DomainEventType `json:"type"`
+	StreamID string          `json:"stream_id"` // Task ID, or agent ID for reputation records
+	ActorID  string          `json:"actor_id"`  // Engine that made the change
+	Data     json.RawMessage `json:"data"`
+	At       time.Time       `json:"at"`
+}
+
+// TaskDecomposition is the payload of a task_decomposed event.
+type TaskDecomposition struct {
+	ParentTaskID string   `json:"parent_task_id"`
+	SubTaskIDs   []string `json:"sub_task_ids"`
+}
+
+// StateProjection is the state rebuilt from the event log as of a point in time.
+type StateProjection struct {
+	AsOf       time.Time                     `json:"as_of"`
+	Through    map[string]uint64             `json:"through"` // Last sequence applied, by engine stream
+	Tasks      map[string]TaskSpec           `json:"tasks"`
+	Contracts  map[string]DelegationContract `json:"contracts"`
+	Bids       map[string][]Bid              `json:"bids"`       // By task ID
+	Latest     map[string]MonitorEvent       `json:"latest"`     // Latest disclosed event by task ID
+	Reputation map[string]ReputationView     `json:"reputation"` // By agent ID
+}
+
+// ReputationView is an agent's reputation as known at the projection time.
+type ReputationView struct {
+	AgentID    string             `json:"agent_id"`
+	Records    []ReputationRecord `json:"records"`
+	TrustScore float64            `json:"trust_score"`
+}
+
+// ─── Queries ─────────────────────────────────────────────────────────────────
+
+// TaskQuery filters the task catalog. Zero-valued fields do not filter.
+type TaskQuery struct {
+	DelegatorID         string       `json:"delegator_id,omitempty"`
+	DelegateeID         string       `json:"delegatee_id,omitempty"`
+	ParentTaskID        string       `json:"parent_task_id,omitempty"`
+	Statuses            []TaskStatus `json:"statuses,omitempty"`
+	CreatedAfter        time.Time    `json:"created_after,omitempty"`
+	CreatedBefore       time.Time    `json:"created_before,omitempty"`
+	StatusChangedBefore time.Time    `json:"status_changed_before,omitempty"` // e.g. "in bidding for over an hour"
+	Offset              int          `json:"offset,omitempty"`
+	Limit               int          `json:"limit,omitempty"` // 0 = engine default page size
+}
+
+// ContractQuery filters the contract catalog. Zero-valued fields do not filter.
+type ContractQuery struct {
+	DelegatorID   string           `json:"delegator_id,omitempty"`
+	DelegateeID   string           `json:"delegatee_id,omitempty"`
+	PartyID       string           `json:"party_id,omitempty"` // Matches either side of the contract
+	TaskID        string           `json:"task_id,omitempty"`
+	Statuses      []ContractStatus `json:"statuses,omitempty"`
+	CreatedAfter  time.Time        `json:"created_after,omitempty"`
+	CreatedBefore time.Time        `json:"created_before,omitempty"`
+	Offset        int              `json:"offset,omitempty"`
+	Limit         int              `json:"limit,omitempty"`
+}
+
+// TaskPage is one page of TaskQuery results.
+type TaskPage struct {
+	Tasks      []TaskSpec `json:"tasks"`
+	Total      int        `json:"total"`       // Matches before pagination
+	NextOffset int        `json:"next_offset"` // -1 when there are no more results
+}
+
+// ContractPage is one page of ContractQuery results.
+type ContractPage struct {
+	Contracts  []DelegationContract `json:"contracts"`
+	Total      int                  `json:"total"`
+	NextOffset int                  `json:"next_offset"`
+}
+
 // ─── Adaptive Coordination Triggers (Section 4.4) ────────────────────────────
 
 type TriggerType string
@@ -278,14 +846,172 @@ const (
 	TriggerIntBudgetOverrun TriggerType = "budget_overrun"
 	TriggerIntVerifyFail    TriggerType = "verification_failure"
 	TriggerIntUnresponsive  TriggerType = "agent_unresponsive"
+	TriggerIntMonitorBreach TriggerType = "monitoring_violation"
 )
 
 type AdaptiveTrigger struct {
-	TriggerID   string      `json:"trigger_id"`
-	TaskID      string      `json:"task_id"`
-	Type        TriggerType `json:"type"`
-	AgentID     string      `json:"agent_id"`
-	Description string      `json:"description"`
-	Urgent      bool        `json:"urgent"`
-	Timestamp   time.Time   `json:"timestamp"`
+	TriggerID   string           `json:"trigger_id"`
+	TaskID      string           `json:"task_id"`
+	Type        TriggerType      `json:"type"`
+	AgentID     string           `json:"agent_id"`
+	Description string           `json:"description"`
+	Urgent      bool             `json:"urgent"`
+	NewPriority *int             `json:"new_priority,omitempty"` // Target priority for priority_change
+	Evidence    *AnomalyEvidence `json:"evidence,omitempty"`     // Set when raised by the anomaly detector
+	Timestamp   time.Time        `json:"timestamp"`
+}
+
+// AnomalyEvidence explains why the anomaly detector raised a trigger.
+type AnomalyEvidence struct {
+	Rule           string    `json:"rule"` // "progress_regression", "budget_projection", "slow_progress", "stalled"
+	Observed       float64   `json:"observed"`
+	Threshold      float64   `json:"threshold"`
+	Capability     string    `json:"capability,omitempty"` // Baseline used, for statistical rules
+	BaselineMean   float64   `json:"baseline_mean,omitempty"`
+	BaselineStdDev float64   `json:"baseline_std_dev,omitempty"`
+	Samples        int       `json:"samples,omitempty"`
+	EventID        string    `json:"event_id,omitempty"` // Event that tripped the rule
+	Detail         string    `json:"detail"`
+	DetectedAt     time.Time `json:"detected_at"`
+}
+
+// ─── Notifications ───────────────────────────────────────────────────────────
+
+// NotificationKind classifies what a notification reports.
+type NotificationKind string
+
+const (
+	NotifyEscalation    NotificationKind = "escalation"     // A task was handed to human oversight
+	NotifySecurityAlert NotificationKind = "security_alert" // A threat was detected or suspected
+	NotifyTrigger       NotificationKind = "trigger"        // An adaptive coordination trigger fired
+)
+
+// Notification is a message for the people and agents overseeing delegation.
+// Recipients is filled in per route before the notification is delivered.
+type Notification struct {
+	NotificationID  string           `json:"notification_id"`
+	Kind            NotificationKind `json:"kind"`
+	Severity        Criticality      `json:"severity"`
+	TaskID          string           `json:"task_id,omitempty"`
+	TaskCriticality Criticality      `json:"task_criticality,omitempty"`
+	AgentID         string           `json:"agent_id,omitempty"` // Agent the notification is about
+	Title           string           `json:"title"`
+	Body            string           `json:"body"`
+	Route           string           `json:"route,omitempty"`
+	Recipients      []Recipient      `json:"recipients,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
+// Recipient is a registered agent a notification was routed to.
+type Recipient struct {
+	AgentID string    `json:"agent_id"`
+	Name    string    `json:"name"`
+	Type    AgentType `json:"type"`
+	Role    AgentRole `json:"role"`
+	Email   string    `json:"email,omitempty"`   // From the agent's "email" metadata
+	Webhook string    `json:"webhook,omitempty"` // From the agent's "webhook" metadata
+}
+
+// NotificationRoute decides who hears about a notification and how. Every
+// condition that is set must hold; every matching route delivers.
+type NotificationRoute struct {
+	Name           string             `json:"name"`
+	Kinds          []NotificationKind `json:"kinds,omitempty"`           // Empty matches every kind
+	MinSeverity    Criticality        `json:"min_severity,omitempty"`    // Empty matches every severity
+	MinCriticality Criticality        `json:"min_criticality,omitempty"` // Minimum task criticality; empty matches every task
+	Roles          []AgentRole        `json:"roles,omitempty"`           // Registered agents with these roles receive it
+	AgentTypes     []AgentType        `json:"agent_types,omitempty"`     // Narrows recipients by type; empty keeps all
+	Notifiers      []string           `json:"notifiers,omitempty"`       // Named notifiers that deliver it; empty uses all
+}
+
+// Matches reports whether the route applies to n.
+func (r NotificationRoute) Matches(n Notification) bool {
+	if len(r.Kinds) > 0 {
+		found := false
+		for _, k := range r.Kinds {
+			if k == n.Kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.MinSeverity != "" && n.Severity.Rank() < r.MinSeverity.Rank() {
+		return false
+	}
+	if r.MinCriticality != "" && n.TaskCriticality.Rank() < r.MinCriticality.Rank() {
+		return false
+	}
+	return true
+}
+
+// ─── Response Policies (Section 4.4) ─────────────────────────────────────────
+
+// ResponseAction is what the engine does about a trigger.
+type ResponseAction string
+
+const (
+	ActionExtend       ResponseAction = "extend"       // Propose a budget extension to the delegatee
+	ActionReDelegate   ResponseAction = "re_delegate"  // Hand the task to the backup agent or the market
+	ActionEscalate     ResponseAction = "escalate"     // Mark the task disputed and notify overseers
+	ActionPause        ResponseAction = "pause"        // Checkpoint and hold the task until ResumeTask
+	ActionCheckpoint   ResponseAction = "checkpoint"   // Checkpoint and carry on
+	ActionCancel       ResponseAction = "cancel"       // Cancel the task and its subtree
+	ActionReExecute    ResponseAction = "re_execute"   // Send the task back for another attempt
+	ActionReprioritize ResponseAction = "reprioritize" // Apply the trigger's new priority
+	ActionMonitor      ResponseAction = "monitor"      // Take no action; keep watching
+)
+
+// ResponsePolicy is a named, versioned rule set for adaptive responses. Rules
+// are tried in order and the first match decides; if none matches the engine
+// keeps monitoring.
+type ResponsePolicy struct {
+	Name        string         `json:"name"`
+	Version     int            `json:"version"` // Assigned on publish
+	Description string         `json:"description,omitempty"`
+	Rules       []ResponseRule `json:"rules"`
+	PublishedAt time.Time      `json:"published_at,omitempty"`
+}
+
+// ResponseRule maps a trigger situation to an action.
+type ResponseRule struct {
+	Name     string         `json:"name"`
+	When     RuleCondition  `json:"when"`
+	Action   ResponseAction `json:"action"`
+	ExtendBy float64        `json:"extend_by,omitempty"` // Budget fraction for extend (0 = 0.2)
+	Notify   bool           `json:"notify,omitempty"`    // Also send overseers an escalation
+	Reason   string         `json:"reason,omitempty"`
+}
+
+// RuleCondition is the situation a rule applies to. Every condition that is
+// set must hold; an empty condition matches every trigger.
+type RuleCondition struct {
+	TriggerTypes        []TriggerType `json:"trigger_types,omitempty"`
+	Urgent              *bool         `json:"urgent,omitempty"`
+	Reversible          *bool         `json:"reversible,omitempty"`
+	MinCriticality      Criticality   `json:"min_criticality,omitempty"`
+	MaxCriticality      Criticality   `json:"max_criticality,omitempty"`
+	HasContract         *bool         `json:"has_contract,omitempty"`
+	ExtensionsExhausted *bool         `json:"extensions_exhausted,omitempty"` // False when there is no contract
+	MinMaxCost          *float64      `json:"min_max_cost,omitempty"`         // Bounds on the contract's MaxCost
+	MaxMaxCost          *float64      `json:"max_max_cost,omitempty"`
+	MinTrust            *float64      `json:"min_trust,omitempty"` // Bounds on the delegatee's trust score
+	MaxTrust            *float64      `json:"max_trust,omitempty"`
+}
+
+// PolicyDecision records which rule answered a trigger and what was done.
+type PolicyDecision struct {
+	TriggerID     string         `json:"trigger_id"`
+	TaskID        string         `json:"task_id"`
+	PolicyName    string         `json:"policy_name"`
+	PolicyVersion int            `json:"policy_version"`
+	Rule          string         `json:"rule,omitempty"` // Empty when no rule matched
+	Action        ResponseAction `json:"action"`
+	ExtendBy      float64        `json:"extend_by,omitempty"`
+	Notify        bool           `json:"notify,omitempty"`
+	Reason        string         `json:"reason"`
+	DryRun        bool           `json:"dry_run"`
+	DecidedAt     time.Time      `json:"decided_at"`
 }